@@ -0,0 +1,69 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 029: Team Quadrant Records (NET-style)
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Store NET-style quadrant records per team per ratings date, computed by the
+--   Go ratings-sync service right after each Barttorvik snapshot is stored.
+--
+-- Quadrant definitions (opponent rank by game site):
+--   Q1: Home 1-30,    Neutral 1-50,    Away 1-75
+--   Q2: Home 31-75,   Neutral 51-100,  Away 76-135
+--   Q3: Home 76-160,  Neutral 101-200, Away 136-240
+--   Q4: everything else
+--
+-- Notes:
+--   - Opponent rank comes from team_ratings.torvik_rank on as_of_date (NET is
+--     not ingested; Torvik rank is the closest stored proxy).
+--   - Games against opponents without a rating on as_of_date (non-D1) are
+--     excluded, matching NET team-sheet behavior.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS team_quadrant_records (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id         UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    season          INTEGER NOT NULL,
+    as_of_date      DATE NOT NULL,
+
+    q1_wins         INTEGER NOT NULL DEFAULT 0,
+    q1_losses       INTEGER NOT NULL DEFAULT 0,
+    q2_wins         INTEGER NOT NULL DEFAULT 0,
+    q2_losses       INTEGER NOT NULL DEFAULT 0,
+    q3_wins         INTEGER NOT NULL DEFAULT 0,
+    q3_losses       INTEGER NOT NULL DEFAULT 0,
+    q4_wins         INTEGER NOT NULL DEFAULT 0,
+    q4_losses       INTEGER NOT NULL DEFAULT 0,
+
+    rank_source     TEXT NOT NULL DEFAULT 'torvik_rank',
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE (team_id, season, as_of_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_quadrant_records_team_date
+    ON team_quadrant_records(team_id, as_of_date DESC);
+CREATE INDEX IF NOT EXISTS idx_team_quadrant_records_season
+    ON team_quadrant_records(season, as_of_date DESC);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_team_quadrant_records_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_team_quadrant_records_updated_at
+            BEFORE UPDATE ON team_quadrant_records
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+COMMENT ON TABLE team_quadrant_records IS
+    'NET-style quadrant W/L per team per ratings date (opponent rank = torvik_rank on as_of_date)';
+
+COMMENT ON COLUMN team_quadrant_records.rank_source IS
+    'Ranking used to bucket opponents into quadrants (torvik_rank until NET is ingested)';
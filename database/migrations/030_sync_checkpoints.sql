@@ -0,0 +1,45 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 030: Sync Checkpoints (resumable backfills)
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Persist the last fully completed unit of long-running backfills so an
+--   interrupted run can resume instead of starting over.
+--
+-- Usage:
+--   ratings-sync writes one row per backfill range, e.g.
+--     job_name = 'ratings_backfill:2018-2026', last_completed = '2021'
+--   and resumes from the next season when BACKFILL_RESUME=true.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS sync_checkpoints (
+    job_name        TEXT PRIMARY KEY,
+    last_completed  TEXT NOT NULL,
+    units_completed INTEGER NOT NULL DEFAULT 0,
+    rows_written    INTEGER NOT NULL DEFAULT 0,
+    metadata        JSONB DEFAULT '{}'::jsonb,
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_sync_checkpoints_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_sync_checkpoints_updated_at
+            BEFORE UPDATE ON sync_checkpoints
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+COMMENT ON TABLE sync_checkpoints IS
+    'Last fully completed unit per backfill job (resume point after interruption)';
+
+COMMENT ON COLUMN sync_checkpoints.last_completed IS
+    'Identifier of the last unit completed without gaps (e.g. season year)';
@@ -0,0 +1,71 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 031: Conference Aggregate Metrics
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Store per-conference average ratings, tempo, and records per ratings date,
+--   computed by the Go ratings-sync service right after each Barttorvik snapshot
+--   is stored. Used for cross-conference adjustment features and report
+--   summaries (e.g. "Big 12 is +4.2 vs national average").
+--
+-- Notes:
+--   - Conference comes from the snapshot's raw_barttorvik->>'conf', falling back
+--     to teams.conference.
+--   - net_vs_national = avg_net_rating minus the average net rating of all rated
+--     teams (team-weighted, not conference-weighted).
+--   - nonconf_* counts completed games between rated teams of different
+--     conferences; games against unrated (non-D1) opponents are excluded.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS conference_metrics (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    conference      TEXT NOT NULL,
+    season          INTEGER NOT NULL,
+    as_of_date      DATE NOT NULL,
+    team_count      INTEGER NOT NULL,
+
+    avg_adj_o       DECIMAL(6,2) NOT NULL,
+    avg_adj_d       DECIMAL(6,2) NOT NULL,
+    avg_net_rating  DECIMAL(6,2) NOT NULL,
+    avg_tempo       DECIMAL(5,2) NOT NULL,
+    avg_barthag     DECIMAL(5,4),
+    net_vs_national DECIMAL(6,2) NOT NULL,
+    strength_rank   INTEGER NOT NULL,
+
+    wins            INTEGER NOT NULL DEFAULT 0,
+    losses          INTEGER NOT NULL DEFAULT 0,
+    nonconf_wins    INTEGER NOT NULL DEFAULT 0,
+    nonconf_losses  INTEGER NOT NULL DEFAULT 0,
+
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE (conference, season, as_of_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conference_metrics_date
+    ON conference_metrics(as_of_date DESC, strength_rank);
+CREATE INDEX IF NOT EXISTS idx_conference_metrics_conference
+    ON conference_metrics(conference, as_of_date DESC);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_conference_metrics_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_conference_metrics_updated_at
+            BEFORE UPDATE ON conference_metrics
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+COMMENT ON TABLE conference_metrics IS
+    'Per-conference average ratings, tempo, and records per ratings date (computed by ratings-sync)';
+
+COMMENT ON COLUMN conference_metrics.net_vs_national IS
+    'Conference average net rating minus the national per-team average';
@@ -0,0 +1,65 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 032: Ratings Change Events (outbox)
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Per-team change events written by the Go ratings-sync service in the same
+--   transaction as each Barttorvik snapshot. Downstream consumers (prediction
+--   engine, feature cache) re-compute only games involving changed teams.
+--
+-- Semantics:
+--   - One row per (team, rating_date) whose adj_o, adj_d, or tempo moved more
+--     than RATINGS_CHANGE_THRESHOLD vs previous_date, or that has no rating on
+--     previous_date (is_new_team).
+--   - Consumers select rows WHERE processed_at IS NULL and set processed_at
+--     once handled. Re-syncing a day refreshes the deltas and clears
+--     processed_at.
+--   - A NOTIFY on channel 'ratings_changed' (JSON summary payload) is sent on
+--     commit for consumers that LISTEN instead of polling.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS ratings_change_events (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id             UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    rating_date         DATE NOT NULL,
+    previous_date       DATE NOT NULL,
+
+    adj_o_delta         DECIMAL(6,2),
+    adj_d_delta         DECIMAL(6,2),
+    tempo_delta         DECIMAL(5,2),
+    net_rating_delta    DECIMAL(6,2),
+    is_new_team         BOOLEAN NOT NULL DEFAULT FALSE,
+
+    processed_at        TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE (team_id, rating_date)
+);
+
+-- Consumers poll for unprocessed events
+CREATE INDEX IF NOT EXISTS idx_ratings_change_events_unprocessed
+    ON ratings_change_events(rating_date)
+    WHERE processed_at IS NULL;
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_ratings_change_events_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_ratings_change_events_updated_at
+            BEFORE UPDATE ON ratings_change_events
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+COMMENT ON TABLE ratings_change_events IS
+    'Outbox of per-team ratings changes per snapshot (written by ratings-sync in the snapshot transaction)';
+
+COMMENT ON COLUMN ratings_change_events.processed_at IS
+    'Set by the consumer once affected games are recomputed; NULL = pending';
@@ -0,0 +1,55 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 033: Team Rating Percentiles and Z-Scores
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Store national percentile ranks and z-scores for each Barttorvik metric per
+--   team per ratings date, computed by the Go ratings-sync service right after
+--   each snapshot is stored, so reports and features can use normalized values
+--   without recomputing distributions at query time.
+--
+-- Orientation:
+--   - percentile is 0-100 where 100 = best in the country (for adj_d, efgd,
+--     tor, ftrd, two_pt_pct_d, three_pt_pct_d lower raw values are better).
+--   - z_score uses the same orientation: positive = better than average.
+--   - tempo and 3P rates have no "better"; high percentile = high raw value.
+--   - Teams with a NULL metric are excluded from that metric's distribution.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS team_rating_percentiles (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id         UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    rating_date     DATE NOT NULL,
+    metric          TEXT NOT NULL,      -- team_ratings column name, e.g. 'adj_o', 'efg'
+
+    value           DECIMAL(8,4) NOT NULL,
+    percentile      DECIMAL(5,2) NOT NULL,
+    z_score         DECIMAL(6,3),       -- NULL when every team has the same value
+
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE (team_id, rating_date, metric)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_rating_percentiles_date_metric
+    ON team_rating_percentiles(rating_date DESC, metric);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_team_rating_percentiles_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_team_rating_percentiles_updated_at
+            BEFORE UPDATE ON team_rating_percentiles
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+COMMENT ON TABLE team_rating_percentiles IS
+    'National percentile (100 = best) and z-score (positive = better) per team, metric, and ratings date';
@@ -0,0 +1,67 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 034: Opponent-Adjusted Recent Form
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Store each team's opponent-adjusted form over its last 5 completed games,
+--   computed by the Go ratings-sync service after each snapshot, for the
+--   feature pipeline.
+--
+-- Definition (per game, then averaged over the window):
+--   expected_margin = (team_net - opp_net) * avg_tempo / 100 + site * 5.8
+--     - team_net/opp_net/tempo come from each team's latest team_ratings row
+--       before the game's Eastern date (ratings going into the game; a same-day
+--       snapshot may already include the result)
+--     - site = +1 home, -1 away, 0 neutral; 5.8 = prediction-service FG HCA
+--   adjusted_margin = avg_margin - avg_expected_margin
+--     (points per game better than the ratings expected)
+--
+-- Notes:
+--   - Games where either team has no ratings snapshot yet (non-D1 opponents,
+--     early-season games) are skipped and older games fill the window, so
+--     games_used is below window_games only when too few eligible games exist.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS team_recent_form (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id             UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    season              INTEGER NOT NULL,
+    as_of_date          DATE NOT NULL,
+
+    window_games        INTEGER NOT NULL,
+    games_used          INTEGER NOT NULL,
+    avg_margin          DECIMAL(6,2) NOT NULL,
+    avg_expected_margin DECIMAL(6,2) NOT NULL,
+    adjusted_margin     DECIMAL(6,2) NOT NULL,
+    last_game_at        TIMESTAMPTZ,
+
+    created_at          TIMESTAMPTZ DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE (team_id, season, as_of_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_recent_form_team_date
+    ON team_recent_form(team_id, as_of_date DESC);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_team_recent_form_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_team_recent_form_updated_at
+            BEFORE UPDATE ON team_recent_form
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+COMMENT ON TABLE team_recent_form IS
+    'Opponent-adjusted last-5 form per team per ratings date (ratings at game time, computed by ratings-sync)';
+
+COMMENT ON COLUMN team_recent_form.adjusted_margin IS
+    'Average actual margin minus margin expected from ratings at game time; positive = outperforming';
@@ -0,0 +1,76 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 035: Provider Outages / Degraded-Data Windows
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Record windows where an upstream data provider was failing or serving
+--   degraded data, so predictions generated inside a window can be annotated
+--   and excluded from later performance analysis.
+--
+-- Lifecycle:
+--   - A failing run opens an outage (ended_at NULL) or bumps failure_count on
+--     the one already open; at most one open outage per provider.
+--   - The next successful run sets ended_at.
+--   - Written today by the Go ratings-sync service (provider 'barttorvik'):
+--     kind 'fetch_failed' after request retries are exhausted, 'partial_snapshot'
+--     when truncated payloads persist through partial-snapshot retries.
+--
+-- Analysis:
+--   provider_degraded_at(provider, ts) is TRUE when ts falls inside an outage
+--   (open outages extend to now).
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS provider_outages (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider        TEXT NOT NULL,      -- 'barttorvik', 'the_odds_api', ...
+    kind            TEXT NOT NULL,      -- 'fetch_failed', 'partial_snapshot', ...
+    started_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ended_at        TIMESTAMPTZ,
+    failure_count   INTEGER NOT NULL DEFAULT 1,
+    last_error      TEXT,
+
+    created_at      TIMESTAMPTZ DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- One open outage per provider (target of ON CONFLICT in ratings-sync)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_provider_outages_open
+    ON provider_outages(provider)
+    WHERE ended_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_provider_outages_window
+    ON provider_outages(provider, started_at DESC);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_provider_outages_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_provider_outages_updated_at
+            BEFORE UPDATE ON provider_outages
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION provider_degraded_at(p_provider TEXT, p_ts TIMESTAMPTZ)
+RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM provider_outages
+        WHERE provider = p_provider
+          AND started_at <= p_ts
+          AND COALESCE(ended_at, NOW()) >= p_ts
+    );
+$$ LANGUAGE sql STABLE;
+
+COMMENT ON TABLE provider_outages IS
+    'Windows where an upstream provider was failing or serving degraded data (ended_at NULL = ongoing)';
+
+COMMENT ON FUNCTION provider_degraded_at(TEXT, TIMESTAMPTZ) IS
+    'TRUE if the provider was in a recorded outage at the given time; use to exclude degraded predictions from analysis';
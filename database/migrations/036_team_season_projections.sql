@@ -0,0 +1,111 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 036: Projected Season Standings
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Store Monte Carlo projections of each team's final record and conference
+--   finish, computed by the Go ratings-sync service from the latest ratings and
+--   the remaining schedule in games.
+--
+-- Method:
+--   - Each unplayed game's home win probability is
+--       Phi(expected_margin / 11), expected_margin as in team_recent_form
+--     (latest ratings, 5.8 home court, 0 on neutral sites).
+--   - The rest of the season is played `runs` times (10,000).
+--   - Conference finish ranks members by conference wins; ties share the
+--     better rank and split the title (conf_title_prob).
+--
+-- Refresh:
+--   Recomputed by a sync once the latest projection for the season is 7+ days
+--   old, so as_of_date moves weekly.
+--
+-- Notes:
+--   - current_wins/losses are Barttorvik's record; current_conf_* count
+--     completed games in games between same-conference teams.
+--   - The remaining schedule is only what odds ingestion has already loaded,
+--     so remaining_games grows as books list more games.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS team_season_projections (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id             UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    season              INTEGER NOT NULL,
+    as_of_date          DATE NOT NULL,
+    conference          TEXT,
+    runs                INTEGER NOT NULL,
+
+    current_wins        INTEGER NOT NULL,
+    current_losses      INTEGER NOT NULL,
+    current_conf_wins   INTEGER NOT NULL,
+    current_conf_losses INTEGER NOT NULL,
+    remaining_games     INTEGER NOT NULL,
+
+    proj_wins           DECIMAL(5,2) NOT NULL,
+    proj_losses         DECIMAL(5,2) NOT NULL,
+    proj_wins_p10       INTEGER NOT NULL,
+    proj_wins_p90       INTEGER NOT NULL,
+    proj_conf_wins      DECIMAL(5,2) NOT NULL,
+    proj_conf_losses    DECIMAL(5,2) NOT NULL,
+    avg_conf_rank       DECIMAL(5,2),
+    conf_title_prob     DECIMAL(5,4) NOT NULL,
+    win_distribution    JSONB NOT NULL,     -- {"<final wins>": probability, ...}
+
+    created_at          TIMESTAMPTZ DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE (team_id, season, as_of_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_season_projections_conf
+    ON team_season_projections(season, as_of_date DESC, conference);
+
+-- Keep updated_at current
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_trigger
+        WHERE tgname = 'trigger_team_season_projections_updated_at'
+    ) THEN
+        CREATE TRIGGER trigger_team_season_projections_updated_at
+            BEFORE UPDATE ON team_season_projections
+            FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+    END IF;
+END
+$$;
+
+-- Report: latest projection per season, ordered as a standings table
+CREATE OR REPLACE VIEW projected_conference_standings AS
+SELECT
+    p.season,
+    p.as_of_date,
+    p.conference,
+    t.canonical_name AS team,
+    p.current_wins,
+    p.current_losses,
+    p.current_conf_wins,
+    p.current_conf_losses,
+    p.proj_wins,
+    p.proj_losses,
+    p.proj_wins_p10,
+    p.proj_wins_p90,
+    p.proj_conf_wins,
+    p.proj_conf_losses,
+    p.avg_conf_rank,
+    p.conf_title_prob
+FROM team_season_projections p
+JOIN teams t ON t.id = p.team_id
+WHERE p.as_of_date = (
+    SELECT MAX(as_of_date) FROM team_season_projections WHERE season = p.season
+)
+ORDER BY p.season, p.conference, p.proj_conf_wins DESC, p.proj_wins DESC;
+
+COMMENT ON TABLE team_season_projections IS
+    'Weekly Monte Carlo projections of final record and conference finish per team (computed by ratings-sync)';
+
+COMMENT ON COLUMN team_season_projections.conf_title_prob IS
+    'Share of simulations finishing first in conference wins; ties split evenly';
+
+COMMENT ON VIEW projected_conference_standings IS
+    'Latest season projections per team, ordered by conference and projected conference wins';
@@ -0,0 +1,56 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 037: Team Alias Conflicts View
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   One row per team name that resolves to more than one team, so every
+--   consumer sees the same conflict list:
+--     - Go ratings-sync logs them before each sync (`ratings-sync aliases check`
+--       lists them with a suggested fix)
+--     - validate_team_matching.py reports them as the "Alias Conflicts" check
+--       (FAIL when a Barttorvik mapping is involved, else WARN)
+--
+-- Matching:
+--   Names are compared case- and whitespace-insensitively across team_aliases
+--   (all sources), teams.barttorvik_name and teams.canonical_name.
+--
+-- Resolution:
+--   canonical_owner is the team whose canonical_name is the conflicting name
+--   (NULL if none, or if several teams share it - see the Duplicate Teams
+--   check). Such a name can only be resolved to its owner, or by removing the
+--   other teams' aliases.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+CREATE OR REPLACE VIEW team_alias_conflicts AS
+WITH names AS (
+    SELECT LOWER(TRIM(alias)) AS name, alias AS raw, team_id, source AS origin
+    FROM team_aliases
+    UNION ALL
+    SELECT LOWER(TRIM(barttorvik_name)), barttorvik_name, id, 'teams.barttorvik_name'
+    FROM teams
+    WHERE barttorvik_name IS NOT NULL
+    UNION ALL
+    SELECT LOWER(TRIM(canonical_name)), canonical_name, id, 'teams.canonical_name'
+    FROM teams
+)
+SELECT
+    n.name,
+    array_agg(DISTINCT t.canonical_name ORDER BY t.canonical_name) AS teams,
+    array_agg(n.origin || ': ' || n.raw || ' -> ' || t.canonical_name
+              ORDER BY n.origin, t.canonical_name) AS mappings,
+    bool_or(n.origin IN ('barttorvik', 'teams.barttorvik_name')) AS affects_ratings,
+    CASE
+        WHEN COUNT(*) FILTER (WHERE n.origin = 'teams.canonical_name') = 1
+        THEN MAX(t.canonical_name) FILTER (WHERE n.origin = 'teams.canonical_name')
+    END AS canonical_owner
+FROM names n
+JOIN teams t ON t.id = n.team_id
+GROUP BY n.name
+HAVING COUNT(DISTINCT n.team_id) > 1;
+
+COMMENT ON VIEW team_alias_conflicts IS
+    'Team names (case/whitespace-insensitive) mapped to more than one team across aliases, barttorvik_name and canonical_name';
+
+COMMENT ON COLUMN team_alias_conflicts.canonical_owner IS
+    'Team whose canonical_name is this name; the only valid resolution target when set';
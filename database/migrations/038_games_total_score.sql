@@ -0,0 +1,19 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 038: games.total_score
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   Final combined score as a generated column, so totals grading and analysis
+--   read one column instead of re-adding home_score + away_score everywhere.
+--
+-- Notes:
+--   - NULL until both scores are recorded.
+--   - Checked at startup by the Go ratings-sync schema check (schema.go).
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+ALTER TABLE games
+    ADD COLUMN IF NOT EXISTS total_score INTEGER
+    GENERATED ALWAYS AS (home_score + away_score) STORED;
+
+COMMENT ON COLUMN games.total_score IS 'home_score + away_score (generated; NULL until both are set)';
@@ -0,0 +1,61 @@
+-- ═══════════════════════════════════════════════════════════════════════════════
+-- MIGRATION 039: Provider Outage Threshold + Degraded Predictions
+-- ═══════════════════════════════════════════════════════════════════════════════
+--
+-- Purpose:
+--   - A single failed run is usually transient. An open provider_outages row now
+--     only counts as an outage once confirmed_at is set, after
+--     OUTAGE_FAILURE_THRESHOLD consecutive failed runs (ratings-sync, default 2).
+--     The window still starts at the first failure (started_at).
+--   - degraded_predictions lists every prediction made inside a confirmed
+--     outage window, so performance analysis can exclude them.
+--
+-- Lifecycle (see migration 035):
+--   - Unconfirmed rows are deleted by the next successful run.
+--   - Confirmed rows get ended_at set by the next successful run.
+--
+-- ═══════════════════════════════════════════════════════════════════════════════
+
+ALTER TABLE provider_outages ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMPTZ;
+
+-- Closed rows recorded before this migration keep counting as outages rather
+-- than guessing; an open one is confirmed by its next failure if it has
+-- reached the threshold by then
+UPDATE provider_outages
+SET confirmed_at = started_at
+WHERE confirmed_at IS NULL AND ended_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION provider_degraded_at(p_provider TEXT, p_ts TIMESTAMPTZ)
+RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+        SELECT 1
+        FROM provider_outages
+        WHERE provider = p_provider
+          AND confirmed_at IS NOT NULL
+          AND started_at <= p_ts
+          AND COALESCE(ended_at, NOW()) >= p_ts
+    );
+$$ LANGUAGE sql STABLE;
+
+-- One row per (prediction, outage) overlap
+CREATE OR REPLACE VIEW degraded_predictions AS
+SELECT
+    p.id AS prediction_id,
+    p.game_id,
+    p.model_version,
+    p.created_at AS predicted_at,
+    o.provider,
+    o.kind AS outage_kind,
+    o.started_at AS outage_started_at,
+    o.ended_at AS outage_ended_at
+FROM predictions p
+JOIN provider_outages o
+  ON o.confirmed_at IS NOT NULL
+ AND o.started_at <= p.created_at
+ AND COALESCE(o.ended_at, NOW()) >= p.created_at;
+
+COMMENT ON COLUMN provider_outages.confirmed_at IS
+    'When failure_count reached the outage threshold; NULL = transient failures, not (yet) an outage';
+
+COMMENT ON VIEW degraded_predictions IS
+    'Predictions made during a confirmed provider outage; exclude prediction_id from performance analysis';
@@ -228,6 +228,287 @@ fn read_secret_file(file_path: &str, secret_name: &str) -> Result<String> {
         ))
 }
 
+// ─────────────────────────────────────────────────────────────────────────────
+// CONFIG PROFILES: `odds-ingestion config validate|print`
+// Same profile names and checks as `ratings-sync config` (Go service).
+// ─────────────────────────────────────────────────────────────────────────────
+
+/// Developer machine: DATABASE_URL/REDIS_URL env, no password or TLS required
+const PROFILE_LOCAL: &str = "local";
+/// Docker Compose: URLs built from /run/secrets/*, services have no TLS
+const PROFILE_COMPOSE: &str = "compose";
+/// Azure Container Apps: URLs from env, TLS required (azure/main.bicep)
+const PROFILE_AZURE: &str = "azure";
+
+/// Numeric overrides that from_env silently replaces with defaults when malformed
+const NUMERIC_ENV: [&str; 2] = ["HEALTH_PORT", "RATE_LIMIT_PER_MINUTE"];
+
+/// The explicit profile, else CONFIG_PROFILE, else one inferred from DATABASE_URL
+fn resolve_profile(explicit: Option<&str>) -> Result<String> {
+    let from_env = env::var("CONFIG_PROFILE").ok();
+    let profile = explicit
+        .or(from_env.as_deref())
+        .map(|p| p.trim().to_lowercase())
+        .filter(|p| !p.is_empty());
+    match profile {
+        None => Ok(infer_profile(env::var("DATABASE_URL").ok().as_deref())),
+        Some(p) if [PROFILE_LOCAL, PROFILE_COMPOSE, PROFILE_AZURE].contains(&p.as_str()) => Ok(p),
+        Some(p) => Err(anyhow!(
+            "unknown config profile {:?} (want {}, {}, or {})",
+            p, PROFILE_LOCAL, PROFILE_COMPOSE, PROFILE_AZURE
+        )),
+    }
+}
+
+/// Guesses the profile for deployments that predate CONFIG_PROFILE: no
+/// DATABASE_URL means Compose secrets; Azure URLs always carry sslmode=require
+fn infer_profile(database_url: Option<&str>) -> String {
+    let url = match database_url.map(str::trim).filter(|u| !u.is_empty()) {
+        Some(url) => url,
+        None => return PROFILE_COMPOSE.to_string(),
+    };
+    let profile = match ConnUrl::parse(url) {
+        Some(u) if u.host == "postgres" => PROFILE_COMPOSE,
+        Some(u) if u.tls_required() => PROFILE_AZURE,
+        _ => PROFILE_LOCAL,
+    };
+    profile.to_string()
+}
+
+/// The parts of a postgres:// or redis:// URL the profile checks look at
+struct ConnUrl<'a> {
+    scheme: &'a str,
+    user_info: Option<&'a str>,
+    host: &'a str,
+    query: &'a str,
+}
+
+impl<'a> ConnUrl<'a> {
+    fn parse(raw: &'a str) -> Option<Self> {
+        let (scheme, rest) = raw.split_once("://")?;
+        let (rest, query) = rest.split_once('?').unwrap_or((rest, ""));
+        let authority = rest.split('/').next().unwrap_or("");
+        let (user_info, host_port) = match authority.rsplit_once('@') {
+            Some((user_info, host_port)) => (Some(user_info), host_port),
+            None => (None, authority),
+        };
+        let host = match host_port.strip_prefix('[') {
+            // IPv6 literal: [::1]:5432
+            Some(v6) => v6.split(']').next().unwrap_or(""),
+            None => host_port.split(':').next().unwrap_or(""),
+        };
+        Some(Self { scheme, user_info, host, query })
+    }
+
+    fn has_password(&self) -> bool {
+        self.user_info
+            .and_then(|u| u.split_once(':'))
+            .map(|(_, password)| !password.is_empty())
+            .unwrap_or(false)
+    }
+
+    fn param(&self, key: &str) -> Option<&'a str> {
+        let query: &'a str = self.query;
+        query
+            .split('&')
+            .filter_map(|kv| kv.split_once('='))
+            .find(|(k, _)| *k == key)
+            .map(|(_, v)| v)
+    }
+
+    /// sslmode=require/verify-ca/verify-full for Postgres (sqlx also accepts
+    /// ssl-mode), rediss:// for Redis
+    fn tls_required(&self) -> bool {
+        if self.scheme == "rediss" {
+            return true;
+        }
+        let mode = self.param("sslmode").or_else(|| self.param("ssl-mode")).unwrap_or("prefer");
+        matches!(mode, "require" | "verify-ca" | "verify-full")
+    }
+
+    fn is_local_host(&self) -> bool {
+        self.host.is_empty()
+            || self.host == "localhost"
+            || self.host == "postgres"
+            || self.host == "redis"
+            || self
+                .host
+                .parse::<std::net::IpAddr>()
+                .map(|ip| ip.is_loopback())
+                .unwrap_or(false)
+    }
+}
+
+/// Masks the password in a connection URL so it is safe to paste into tickets/logs
+fn redact_url(raw: &str) -> String {
+    let u = match ConnUrl::parse(raw) {
+        Some(u) => u,
+        None if raw.is_empty() => return String::new(),
+        None => return "<unparseable, redacted>".to_string(),
+    };
+    match u.user_info.and_then(|info| info.split_once(':')) {
+        Some((user, password)) if !password.is_empty() => raw.replacen(
+            &format!("{}:{}@", user, password),
+            &format!("{}:xxxxx@", user),
+            1,
+        ),
+        _ => raw.to_string(),
+    }
+}
+
+impl Config {
+    /// Every problem with this config for the profile, so a misconfigured
+    /// deployment is caught by `config validate` instead of mid-sync
+    pub fn validate(&self, profile: &str) -> Vec<String> {
+        let mut problems = Vec::new();
+
+        for key in NUMERIC_ENV {
+            if let Ok(v) = env::var(key) {
+                if !v.is_empty() && v.parse::<u32>().is_err() {
+                    problems.push(format!("{}={:?} is not a number (default used)", key, v));
+                }
+            }
+        }
+
+        for (name, url, schemes) in [
+            ("DATABASE_URL", &self.database_url, ["postgres", "postgresql"]),
+            ("REDIS_URL", &self.redis_url, ["redis", "rediss"]),
+        ] {
+            let u = match ConnUrl::parse(url) {
+                Some(u) if schemes.contains(&u.scheme) => u,
+                Some(u) => {
+                    problems.push(format!("{} scheme {:?} must be {} or {}", name, u.scheme, schemes[0], schemes[1]));
+                    continue;
+                }
+                None => {
+                    problems.push(format!("{} is not a {}:// URL", name, schemes[0]));
+                    continue;
+                }
+            };
+            if u.host.is_empty() {
+                problems.push(format!("{} has no host", name));
+            }
+            if !u.has_password() && profile != PROFILE_LOCAL {
+                problems.push(format!("{} has no password", name));
+            }
+            match profile {
+                PROFILE_AZURE if !u.tls_required() => problems.push(format!(
+                    "azure profile requires TLS for {} (sslmode=require for Postgres, rediss:// for Redis)",
+                    name
+                )),
+                PROFILE_AZURE if u.is_local_host() => problems.push(format!(
+                    "azure profile {} points at {:?}, not an Azure server",
+                    name, u.host
+                )),
+                PROFILE_COMPOSE if u.tls_required() => problems.push(format!(
+                    "compose profile services have no TLS; {} will fail to connect",
+                    name
+                )),
+                _ => {}
+            }
+        }
+
+        if !self.run_once {
+            problems.push("RUN_ONCE=false is not supported; this service is manual-only".to_string());
+        }
+        if !(self.enable_full || self.enable_h1 || self.enable_h2) {
+            problems.push("ENABLE_FULL, ENABLE_H1 and ENABLE_H2 are all false; nothing would be fetched".to_string());
+        }
+        if self.allow_team_creation && profile == PROFILE_AZURE {
+            problems.push("ALLOW_TEAM_CREATION=true is for controlled local backfills, not the azure profile".to_string());
+        }
+        problems
+    }
+
+    /// Writes the effective configuration, one key per line. With redact, the
+    /// API key and connection passwords are masked.
+    pub fn print(&self, profile: &str, redact: bool) {
+        let show_url = |url: &str| if redact { redact_url(url) } else { url.to_string() };
+        let api_key = if redact { "xxxxx".to_string() } else { self.odds_api_key.clone() };
+
+        println!("profile={}", profile);
+        println!("database_url={}", show_url(&self.database_url));
+        println!("redis_url={}", show_url(&self.redis_url));
+        println!("odds_api_key={}", api_key);
+        println!("sport_key={}", self.sport_key);
+        println!("health_port={}", self.health_port);
+        println!("run_once={}", self.run_once);
+        println!("enable_full={}", self.enable_full);
+        println!("enable_h1={}", self.enable_h1);
+        println!("enable_h2={}", self.enable_h2);
+        println!("markets_full={}", self.markets_full);
+        println!("markets_h1={}", self.markets_h1);
+        println!("markets_h2={}", self.markets_h2);
+        println!("bookmakers_h1={}", self.bookmakers_h1);
+        println!("bookmakers_h2={}", self.bookmakers_h2);
+        println!("strict_team_matching={}", self.strict_team_matching);
+        println!("allow_team_creation={}", self.allow_team_creation);
+        println!("enable_fuzzy_resolution={}", self.enable_fuzzy_resolution);
+        println!("rate_limit_per_minute={}", self.rate_limit_per_minute);
+    }
+}
+
+/// Implements `odds-ingestion config validate|print [--profile P] [--redacted=false]`
+/// and returns the process exit code
+fn run_config_command(args: &[String]) -> i32 {
+    let usage = "usage: odds-ingestion config validate|print [--profile local|compose|azure] [--redacted=true]";
+    let mut explicit_profile: Option<String> = None;
+    let mut redact = true;
+
+    let mut rest = args.iter().skip(1);
+    while let Some(arg) = rest.next() {
+        match arg.trim_start_matches('-').split_once('=') {
+            Some(("profile", v)) => explicit_profile = Some(v.to_string()),
+            Some(("redacted", v)) => redact = v != "false",
+            None if arg.trim_start_matches('-') == "profile" => explicit_profile = rest.next().cloned(),
+            None if arg.trim_start_matches('-') == "redacted" => redact = true,
+            _ => {
+                eprintln!("{}", usage);
+                return 1;
+            }
+        }
+    }
+
+    let profile = match resolve_profile(explicit_profile.as_deref()) {
+        Ok(profile) => profile,
+        Err(e) => {
+            eprintln!("{}", e);
+            return 1;
+        }
+    };
+    // Secrets that can't be read (compose) or placeholder API keys fail here
+    let config = match Config::from_env() {
+        Ok(config) => config,
+        Err(e) => {
+            eprintln!("config INVALID (profile={}):\n  - {:#}", profile, e);
+            return 1;
+        }
+    };
+
+    match args.first().map(String::as_str) {
+        Some("print") => {
+            config.print(&profile, redact);
+            0
+        }
+        Some("validate") => {
+            let problems = config.validate(&profile);
+            if problems.is_empty() {
+                println!("config OK (profile={})", profile);
+                return 0;
+            }
+            eprintln!("config INVALID (profile={}):", profile);
+            for problem in problems {
+                eprintln!("  - {}", problem);
+            }
+            1
+        }
+        _ => {
+            eprintln!("{}", usage);
+            1
+        }
+    }
+}
+
 /// Thread-safe game cache with proper entry API to avoid race conditions
 #[derive(Clone)]
 pub struct GameCache {
@@ -1682,6 +1963,12 @@ async fn health_handler(
 async fn main() -> Result<()> {
     // NO .env file loading - all secrets MUST come from Docker secret files
 
+    // `odds-ingestion config validate|print` checks a deployment without syncing
+    let args: Vec<String> = env::args().skip(1).collect();
+    if args.first().map(String::as_str) == Some("config") {
+        std::process::exit(run_config_command(&args[1..]));
+    }
+
     // Initialize tracing
     tracing_subscriber::fmt()
         .with_env_filter(
@@ -1693,6 +1980,14 @@ async fn main() -> Result<()> {
     info!("NCAA Basketball Odds Ingestion Service v6.0");
 
     let config = Config::from_env()?;
+    let profile = resolve_profile(None)?;
+    let problems = config.validate(&profile);
+    if !problems.is_empty() {
+        for problem in &problems {
+            error!("Invalid configuration (profile={}): {}", profile, problem);
+        }
+        return Err(anyhow!("{} configuration problem(s); run `odds-ingestion config validate`", problems.len()));
+    }
     let health_port = config.health_port;
     let run_once = config.run_once;
     
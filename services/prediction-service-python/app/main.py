@@ -1644,7 +1644,7 @@ def _fetch_latest_team_ratings(engine, team_name: str) -> TeamRatings:
         three_pt_rate=float(row.three_pt_rate),
         three_pt_rate_d=float(row.three_pt_rate_d),
         barthag=float(row.barthag),
-        wab=float(row.wab),
+        wab=float(row.wab or 0),
     )
 
 
@@ -852,7 +852,8 @@ def fetch_games_from_db(target_date: date | None = None, engine=None) -> list[di
         games = []
         for row in rows:
             #
-            # v6.3: ALL 22 BARTTORVIK FIELDS ARE REQUIRED - NO FALLBACKS
+            # v6.3: ALL BARTTORVIK FIELDS ARE REQUIRED - NO FALLBACKS
+            # (except WAB, which the feed does not carry and is stored as NULL)
             # If any field is missing, we log an error and skip the game.
             # The data pipeline must ensure complete data before predictions run.
             #
@@ -865,7 +866,7 @@ def fetch_games_from_db(target_date: date | None = None, engine=None) -> list[di
                 row.home_two_pt_pct, row.home_two_pt_pct_d,
                 row.home_three_pt_pct, row.home_three_pt_pct_d,
                 row.home_three_pt_rate, row.home_three_pt_rate_d,
-                row.home_barthag, row.home_wab
+                row.home_barthag,
             ]
 
             # Check away team has ALL required fields
@@ -876,7 +877,7 @@ def fetch_games_from_db(target_date: date | None = None, engine=None) -> list[di
                 row.away_two_pt_pct, row.away_two_pt_pct_d,
                 row.away_three_pt_pct, row.away_three_pt_pct_d,
                 row.away_three_pt_rate, row.away_three_pt_rate_d,
-                row.away_barthag, row.away_wab
+                row.away_barthag,
             ]
 
             # Build home ratings - ALL fields REQUIRED
@@ -910,7 +911,8 @@ def fetch_games_from_db(target_date: date | None = None, engine=None) -> list[di
                     "three_pt_rate_d": float(row.home_three_pt_rate_d),
                     # Quality Metrics
                     "barthag": float(row.home_barthag),
-                    "wab": float(row.home_wab),
+                    # WAB is not in the Barttorvik feed and is stored as NULL
+                    "wab": float(row.home_wab or 0),
                 }
 
             # Build away ratings - ALL fields REQUIRED
@@ -944,7 +946,8 @@ def fetch_games_from_db(target_date: date | None = None, engine=None) -> list[di
                     "three_pt_rate_d": float(row.away_three_pt_rate_d),
                     # Quality Metrics
                     "barthag": float(row.away_barthag),
-                    "wab": float(row.away_wab),
+                    # WAB is not in the Barttorvik feed and is stored as NULL
+                    "wab": float(row.away_wab or 0),
                 }
 
             game = {
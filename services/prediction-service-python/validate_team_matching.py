@@ -78,6 +78,7 @@ class TeamMatchingValidator:
         self._check_games_data_completeness()
         self._check_four_factors_coverage()
         self._check_duplicate_teams()
+        self._check_alias_conflicts()
 
         # Print summary
         return self._print_summary()
@@ -398,6 +399,47 @@ class TeamMatchingValidator:
 
         print()
 
+    def _check_alias_conflicts(self):
+        """Check for names mapped to more than one team (team_alias_conflicts view)."""
+        print("🔀 Alias Conflicts")
+        print("-" * 60)
+
+        try:
+            with self.engine.connect() as conn:
+                conflicts = list(conn.execute(text("""
+                    SELECT name, teams, affects_ratings, canonical_owner
+                    FROM team_alias_conflicts
+                    ORDER BY affects_ratings DESC, name
+                """)))
+        except Exception as e:
+            # View added in migration 037; older databases skip the check
+            self.results.append(ValidationResult("Alias Conflicts", 0, "INFO", "team_alias_conflicts view unavailable"))
+            print(f"   ⚠ Skipped: {type(e).__name__}")
+            print()
+            return
+
+        if conflicts:
+            # A Barttorvik mapping in conflict can store ratings against the wrong team
+            rating_conflicts = sum(1 for row in conflicts if row.affects_ratings)
+            self.results.append(ValidationResult(
+                "Alias Conflicts", len(conflicts), "FAIL" if rating_conflicts else "WARN",
+                f"{len(conflicts)} names map to multiple teams ({rating_conflicts} affect ratings)"
+            ))
+            print(f"   Found {len(conflicts)} conflicting names:")
+            for row in conflicts[:20]:
+                marker = " [affects ratings]" if row.affects_ratings else ""
+                print(f"      \"{row.name}\" -> {' | '.join(row.teams)}{marker}")
+                if row.canonical_owner:
+                    print(f"         Canonical owner: {row.canonical_owner}")
+            if len(conflicts) > 20:
+                print(f"      ... and {len(conflicts) - 20} more")
+            print("   Resolve with: ratings-sync aliases check")
+        else:
+            self.results.append(ValidationResult("Alias Conflicts", 0, "PASS"))
+            print("   ✓ No alias conflicts detected")
+
+        print()
+
     def _print_summary(self) -> bool:
         """Print validation summary. Returns True if all critical checks pass."""
         print("═" * 60)
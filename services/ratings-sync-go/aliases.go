@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// AliasConflict is a team name that resolves to more than one team, as reported
+// by the team_alias_conflicts view (migration 037). The same view feeds the
+// "Alias Conflicts" check in validate_team_matching.py.
+type AliasConflict struct {
+	Name     string
+	Teams    []string // Canonical names of the teams the name maps to
+	Mappings []string // "<origin>: <alias> -> <team>", origin is an alias source or teams column
+	// AffectsRatings is set when a Barttorvik mapping is involved, so snapshots
+	// may be stored against the wrong team
+	AffectsRatings bool
+	// CanonicalOwner is the team whose canonical_name is Name, if exactly one is.
+	// Aliases can only be resolved to that team.
+	CanonicalOwner string
+}
+
+// Fix is the command that clears the conflict
+func (c AliasConflict) Fix() string {
+	if c.CanonicalOwner != "" {
+		return fmt.Sprintf("ratings-sync aliases resolve --name %q --team %q", c.Name, c.CanonicalOwner)
+	}
+	for _, m := range c.Mappings {
+		// Canonical name with no single owner: two teams share it
+		if strings.HasPrefix(m, "teams.canonical_name: ") {
+			return "several teams share this canonical name; merge the duplicate teams first"
+		}
+	}
+	return fmt.Sprintf("ratings-sync aliases resolve --name %q --team <one of: %s>", c.Name, strings.Join(c.Teams, ", "))
+}
+
+// FindAliasConflicts lists every name mapped to two or more teams
+func (r *RatingsSync) FindAliasConflicts(ctx context.Context) ([]AliasConflict, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT name, teams, mappings, affects_ratings, COALESCE(canonical_owner, '')
+		FROM team_alias_conflicts
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("finding alias conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []AliasConflict
+	for rows.Next() {
+		var c AliasConflict
+		if err := rows.Scan(&c.Name, &c.Teams, &c.Mappings, &c.AffectsRatings, &c.CanonicalOwner); err != nil {
+			return nil, fmt.Errorf("scanning alias conflict: %w", err)
+		}
+		conflicts = append(conflicts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("finding alias conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+// reportAliasConflicts logs alias conflicts before a sync. It is a data-quality
+// warning only: resolution order in ensureTeam stays deterministic, but may pick
+// the wrong team until the conflict is resolved with `ratings-sync aliases`.
+func (r *RatingsSync) reportAliasConflicts(ctx context.Context) {
+	if !r.schemaReady("team_alias_conflicts") {
+		return
+	}
+	conflicts, err := r.FindAliasConflicts(ctx)
+	if err != nil {
+		r.logger.Warn("Alias conflict check failed", zap.Error(err))
+		return
+	}
+	for _, c := range conflicts {
+		r.logger.Warn("Team alias conflict",
+			zap.String("name", c.Name),
+			zap.Strings("teams", c.Teams),
+			zap.Strings("mappings", c.Mappings),
+			zap.Bool("affects_ratings", c.AffectsRatings),
+			zap.String("fix", c.Fix()))
+	}
+	if len(conflicts) > 0 {
+		r.logger.Warn("Team alias conflicts found; run `ratings-sync aliases check` to review",
+			zap.Int("conflicts", len(conflicts)))
+	}
+}
+
+// ResolveAlias points every alias row matching name (any source) at the team
+// with the given canonical name, and clears a matching teams.barttorvik_name on
+// other teams. Returns the number of rows changed.
+//
+// A name that is another team's canonical_name can only go to that team: the
+// canonical name itself is not an alias row, so any other target would leave
+// the conflict in place.
+func (r *RatingsSync) ResolveAlias(ctx context.Context, name, canonical string) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var teamID string
+	if err := tx.QueryRow(ctx, `SELECT id FROM teams WHERE canonical_name = $1`, canonical).Scan(&teamID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("no team with canonical_name %q", canonical)
+		}
+		return 0, fmt.Errorf("looking up team: %w", err)
+	}
+
+	var owner string
+	err = tx.QueryRow(ctx, `
+		SELECT canonical_name FROM teams
+		WHERE LOWER(TRIM(canonical_name)) = LOWER(TRIM($1)) AND id <> $2
+		LIMIT 1
+	`, name, teamID).Scan(&owner)
+	if err == nil {
+		return 0, fmt.Errorf("%q is the canonical name of %q; resolve it to that team, or remove the other aliases with `ratings-sync aliases remove`", name, owner)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("checking canonical names: %w", err)
+	}
+
+	aliases, err := tx.Exec(ctx, `
+		UPDATE team_aliases SET team_id = $2
+		WHERE LOWER(TRIM(alias)) = LOWER(TRIM($1)) AND team_id <> $2
+	`, name, teamID)
+	if err != nil {
+		return 0, fmt.Errorf("repointing aliases: %w", err)
+	}
+	names, err := tx.Exec(ctx, `
+		UPDATE teams SET barttorvik_name = NULL
+		WHERE LOWER(TRIM(barttorvik_name)) = LOWER(TRIM($1)) AND id <> $2
+	`, name, teamID)
+	if err != nil {
+		return 0, fmt.Errorf("clearing barttorvik_name: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing transaction: %w", err)
+	}
+	return aliases.RowsAffected() + names.RowsAffected(), nil
+}
+
+// RemoveAlias deletes one alias row, matched case-insensitively within source
+func (r *RatingsSync) RemoveAlias(ctx context.Context, name, source string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM team_aliases
+		WHERE LOWER(TRIM(alias)) = LOWER(TRIM($1)) AND source = $2
+	`, name, source)
+	if err != nil {
+		return 0, fmt.Errorf("removing alias: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// runAliasesCommand implements `ratings-sync aliases check|resolve|remove`.
+// check exits non-zero when conflicts exist so it can gate deploys.
+func runAliasesCommand(args []string, stdout, stderr io.Writer) int {
+	usage := `usage: ratings-sync aliases check
+       ratings-sync aliases resolve --name <alias> --team <canonical_name>
+       ratings-sync aliases remove --name <alias> --source <source>`
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return ExitFatal
+	}
+
+	fs := flag.NewFlagSet("aliases "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("name", "", "alias to resolve or remove (case-insensitive)")
+	team := fs.String("team", "", "canonical_name of the team the alias belongs to")
+	source := fs.String("source", "", "alias source, e.g. barttorvik, the_odds_api")
+	if err := fs.Parse(args[1:]); err != nil {
+		return ExitFatal
+	}
+
+	profile, err := resolveProfile("")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFatal
+	}
+	config, err := loadConfig(profile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFatal
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, config.DatabaseURL)
+	if err != nil {
+		fmt.Fprintln(stderr, "connecting to database:", err)
+		return ExitFatal
+	}
+	defer db.Close()
+	r := NewRatingsSync(db, zap.NewNop(), config)
+
+	switch args[0] {
+	case "check":
+		conflicts, err := r.FindAliasConflicts(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitFatal
+		}
+		if len(conflicts) == 0 {
+			fmt.Fprintln(stdout, "no alias conflicts")
+			return ExitOK
+		}
+		for _, c := range conflicts {
+			marker := ""
+			if c.AffectsRatings {
+				marker = " [affects ratings]"
+			}
+			fmt.Fprintf(stdout, "%q -> %s%s\n", c.Name, strings.Join(c.Teams, " | "), marker)
+			for _, m := range c.Mappings {
+				fmt.Fprintf(stdout, "    %s\n", m)
+			}
+			fmt.Fprintf(stdout, "    fix: %s\n", c.Fix())
+		}
+		fmt.Fprintf(stdout, "%d alias conflict(s)\n", len(conflicts))
+		return ExitFatal
+	case "resolve":
+		if *name == "" || *team == "" {
+			fmt.Fprintln(stderr, "resolve requires --name and --team")
+			return ExitFatal
+		}
+		n, err := r.ResolveAlias(ctx, *name, *team)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitFatal
+		}
+		fmt.Fprintf(stdout, "%q now maps to %s (%d row(s) changed)\n", *name, *team, n)
+		return ExitOK
+	case "remove":
+		if *name == "" || *source == "" {
+			fmt.Fprintln(stderr, "remove requires --name and --source")
+			return ExitFatal
+		}
+		n, err := r.RemoveAlias(ctx, *name, *source)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitFatal
+		}
+		fmt.Fprintf(stdout, "removed %d alias row(s)\n", n)
+		return ExitOK
+	}
+	fmt.Fprintln(stderr, usage)
+	return ExitFatal
+}
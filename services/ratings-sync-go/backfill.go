@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// Checkpoint is the persisted resume point for a backfill job
+type Checkpoint struct {
+	LastCompleted  string
+	UnitsCompleted int
+	RowsWritten    int
+}
+
+// backfillJobName identifies a backfill range in sync_checkpoints
+func backfillJobName(from, to int) string {
+	return fmt.Sprintf("ratings_backfill:%d-%d", from, to)
+}
+
+// estimateRemaining projects time left from the average duration of completed units
+func estimateRemaining(elapsed time.Duration, completed, remaining int) time.Duration {
+	if completed <= 0 {
+		return 0
+	}
+	return elapsed / time.Duration(completed) * time.Duration(remaining)
+}
+
+// Backfill syncs every season in [from, to], logging progress and ETA after each
+// season and persisting a checkpoint so an interrupted run can resume.
+//
+// The checkpoint only advances while seasons succeed without gaps; a failed season
+// is logged and the loop continues, but a resumed run will retry from that season.
+func (r *RatingsSync) Backfill(ctx context.Context, from, to int, resume bool) error {
+	if from > to {
+		from, to = to, from
+	}
+	job := backfillJobName(from, to)
+	total := to - from + 1
+
+	start := from
+	rowsWritten := 0
+	if resume {
+		cp, err := r.loadCheckpoint(ctx, job)
+		if err != nil {
+			return fmt.Errorf("loading checkpoint: %w", err)
+		}
+		if cp != nil {
+			last, err := strconv.Atoi(cp.LastCompleted)
+			if err != nil {
+				return fmt.Errorf("invalid checkpoint %q for %s: %w", cp.LastCompleted, job, err)
+			}
+			if last >= to {
+				r.logger.Info("Backfill already complete per checkpoint", zap.String("job", job))
+				return nil
+			}
+			if last >= from {
+				start = last + 1
+				rowsWritten = cp.RowsWritten
+			}
+			r.logger.Info("Resuming backfill from checkpoint",
+				zap.String("job", job),
+				zap.String("last_completed", cp.LastCompleted),
+				zap.Int("resume_season", start))
+		}
+	}
+
+	r.backfilling = true
+	defer func() { r.backfilling = false }()
+
+	began := time.Now()
+	alreadyDone := start - from
+	contiguous := true
+	failed := 0
+
+	for season := start; season <= to; season++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		r.logger.Info("Backfill season", zap.Int("season", season))
+		r.config.Season = season
+		stats, err := r.Sync(ctx)
+		if err != nil {
+			failed++
+			contiguous = false
+			r.logger.Error("Backfill sync failed", zap.Int("season", season), zap.Error(err))
+		} else {
+			rowsWritten += stats.Stored
+			if contiguous {
+				cp := Checkpoint{
+					LastCompleted:  strconv.Itoa(season),
+					UnitsCompleted: season - from + 1,
+					RowsWritten:    rowsWritten,
+				}
+				if err := r.saveCheckpoint(ctx, job, cp); err != nil {
+					r.logger.Warn("Failed to save backfill checkpoint", zap.String("job", job), zap.Error(err))
+				}
+			}
+		}
+
+		doneThisRun := season - start + 1
+		remaining := to - season
+		r.logger.Info("Backfill progress",
+			zap.String("job", job),
+			zap.Int("seasons_completed", alreadyDone+doneThisRun),
+			zap.Int("seasons_total", total),
+			zap.Int("seasons_failed", failed),
+			zap.Int("rows_written", rowsWritten),
+			zap.Duration("elapsed", time.Since(began).Round(time.Second)),
+			zap.Duration("eta", estimateRemaining(time.Since(began), doneThisRun, remaining).Round(time.Second)))
+	}
+
+	r.logger.Info("Backfill completed",
+		zap.Int("from", from),
+		zap.Int("to", to),
+		zap.Int("seasons_failed", failed),
+		zap.Int("rows_written", rowsWritten),
+		zap.Duration("duration", time.Since(began)))
+
+	if failed > 0 {
+		return &SyncError{
+			Code: ExitBackfillIncomplete,
+			Err:  fmt.Errorf("%d of %d seasons failed (rerun with BACKFILL_RESUME=true to retry from the first failure)", failed, to-start+1),
+		}
+	}
+	return nil
+}
+
+// loadCheckpoint returns the stored checkpoint for job, or nil if none exists
+func (r *RatingsSync) loadCheckpoint(ctx context.Context, job string) (*Checkpoint, error) {
+	var cp Checkpoint
+	err := r.db.QueryRow(ctx, `
+		SELECT last_completed, units_completed, rows_written
+		FROM sync_checkpoints
+		WHERE job_name = $1
+	`, job).Scan(&cp.LastCompleted, &cp.UnitsCompleted, &cp.RowsWritten)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// saveCheckpoint upserts the checkpoint for job
+func (r *RatingsSync) saveCheckpoint(ctx context.Context, job string, cp Checkpoint) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO sync_checkpoints (job_name, last_completed, units_completed, rows_written)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_name) DO UPDATE SET
+			last_completed = EXCLUDED.last_completed,
+			units_completed = EXCLUDED.units_completed,
+			rows_written = EXCLUDED.rows_written
+	`, job, cp.LastCompleted, cp.UnitsCompleted, cp.RowsWritten)
+	return err
+}
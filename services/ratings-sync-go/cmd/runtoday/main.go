@@ -0,0 +1,257 @@
+// NCAA Basketball "Run Today" Orchestrator
+//
+// Runs the daily pipeline in sequence: ratings sync → odds fetch → predictions
+// (picks report) → settlement (grading + ROI report).
+// Each stage is an existing binary/script (Go ratings-sync, Rust odds-ingestion,
+// run_today.py), so this command only sequences them and reports per-stage status.
+// MANUAL-ONLY: runs once and exits. Exits non-zero if any stage fails.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Stage is one step of the daily pipeline
+type Stage struct {
+	Name    string
+	Command []string
+	Env     []string // Extra KEY=VALUE pairs appended to the inherited environment
+	Timeout time.Duration
+}
+
+// StageRunner executes a single stage; the pipeline's sequencing is tested with a fake
+type StageRunner interface {
+	Run(ctx context.Context, stage Stage) error
+}
+
+// StageResult records the outcome of a stage
+type StageResult struct {
+	Name     string
+	Status   string // ok, failed, skipped
+	Duration time.Duration
+	Err      error
+}
+
+func main() {
+	date := flag.String("date", "", "Date to run predictions for (YYYY-MM-DD, defaults to today)")
+	skip := flag.String("skip", "", "Comma-separated stages to skip (ratings,odds,predict,settle)")
+	continueOnError := flag.Bool("continue-on-error", false, "Run remaining stages after a failure (exit code still non-zero)")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("Failed to initialize logger:", err)
+	}
+	defer logger.Sync()
+
+	stages, err := buildStages(*date)
+	if err != nil {
+		logger.Fatal("Invalid configuration", zap.Error(err))
+	}
+
+	skipped := make(map[string]bool)
+	for _, name := range strings.Split(*skip, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			skipped[name] = true
+		}
+	}
+
+	results := runStages(context.Background(), logger, execRunner{}, stages, skipped, *continueOnError)
+	printSummary(results)
+	os.Exit(exitCode(results))
+}
+
+// exitCode is 1 if any stage failed, else 0
+func exitCode(results []StageResult) int {
+	for _, res := range results {
+		if res.Status == "failed" {
+			return 1
+		}
+	}
+	return 0
+}
+
+// buildStages assembles the pipeline from environment configuration.
+// Binary paths and timeouts match the defaults used by run_today.py inside the container.
+func buildStages(date string) ([]Stage, error) {
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("invalid --date %q: use YYYY-MM-DD", date)
+		}
+	}
+
+	// Go/Rust binaries expect standard postgres:// URL, not SQLAlchemy's postgresql+psycopg2://
+	var dbEnv []string
+	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
+		dbEnv = append(dbEnv, "DATABASE_URL="+strings.Replace(dbURL, "+psycopg2", "", 1))
+	}
+
+	python := envOr("PYTHON_BIN", "python")
+	runToday := envOr("RUN_TODAY_SCRIPT", "/app/run_today.py")
+	runTodayArgs := []string{python, runToday, "--no-sync"}
+	if date != "" {
+		runTodayArgs = append(runTodayArgs, "--date", date)
+	}
+
+	oddsEnv := append([]string{
+		"ENABLE_FULL=true",
+		"ENABLE_H1=true",
+		"ENABLE_H2=false",
+		"STRICT_TEAM_MATCHING=true",
+		// odds-ingestion starts a health server; use an ephemeral port for one-shot runs
+		"HEALTH_PORT=0",
+		"RUN_ONCE=true",
+	}, dbEnv...)
+	if key := oddsAPIKey(); key != "" {
+		oddsEnv = append(oddsEnv, "THE_ODDS_API_KEY="+key)
+	}
+
+	return []Stage{
+		{
+			Name:    "ratings",
+			Command: []string{envOr("RATINGS_SYNC_BIN", "/app/bin/ratings-sync")},
+			Env:     append([]string{"RUN_ONCE=true"}, dbEnv...),
+			Timeout: envSeconds("RATINGS_SYNC_TIMEOUT_SECONDS", 180),
+		},
+		{
+			Name:    "odds",
+			Command: []string{envOr("ODDS_INGESTION_BIN", "/app/bin/odds-ingestion")},
+			Env:     oddsEnv,
+			Timeout: envSeconds("RUST_ODDS_SYNC_TIMEOUT_SECONDS", 240),
+		},
+		{
+			Name:    "predict",
+			Command: append(append([]string{}, runTodayArgs...), "--no-settle"),
+			Timeout: envSeconds("PREDICT_TIMEOUT_SECONDS", 900),
+		},
+		{
+			// Grades finished games and prints the ROI report; the picks report
+			// comes from the predict stage
+			Name:    "settle",
+			Command: append(append([]string{}, runTodayArgs...), "--settle-only"),
+			Timeout: envSeconds("SETTLE_TIMEOUT_SECONDS", 300),
+		},
+	}, nil
+}
+
+// runStages executes stages in order. After a failure, remaining stages are
+// skipped unless continueOnError is set.
+func runStages(ctx context.Context, logger *zap.Logger, runner StageRunner, stages []Stage, skip map[string]bool, continueOnError bool) []StageResult {
+	results := make([]StageResult, 0, len(stages))
+	failed := false
+
+	for _, stage := range stages {
+		if skip[stage.Name] || (failed && !continueOnError) {
+			logger.Info("Stage skipped", zap.String("stage", stage.Name))
+			results = append(results, StageResult{Name: stage.Name, Status: "skipped"})
+			continue
+		}
+
+		logger.Info("Stage starting", zap.String("stage", stage.Name), zap.Strings("command", stage.Command))
+		start := time.Now()
+		err := runner.Run(ctx, stage)
+		res := StageResult{Name: stage.Name, Status: "ok", Duration: time.Since(start), Err: err}
+
+		if err != nil {
+			res.Status = "failed"
+			failed = true
+			logger.Error("Stage failed",
+				zap.String("stage", stage.Name),
+				zap.Duration("duration", res.Duration),
+				zap.Error(err))
+		} else {
+			logger.Info("Stage completed",
+				zap.String("stage", stage.Name),
+				zap.Duration("duration", res.Duration))
+		}
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// execRunner runs stages as child processes
+type execRunner struct{}
+
+// Run runs a single stage with its timeout, streaming output to this process
+func (execRunner) Run(ctx context.Context, stage Stage) error {
+	ctx, cancel := context.WithTimeout(ctx, stage.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, stage.Command[0], stage.Command[1:]...)
+	cmd.Env = append(os.Environ(), stage.Env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", stage.Timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exited with code %d", exitErr.ExitCode())
+	}
+	return err
+}
+
+func printSummary(results []StageResult) {
+	fmt.Println()
+	fmt.Println("Run today summary:")
+	for _, res := range results {
+		tag := "[OK]  "
+		switch res.Status {
+		case "failed":
+			tag = "[FAIL]"
+		case "skipped":
+			tag = "[SKIP]"
+		}
+		line := fmt.Sprintf("  %s %-8s", tag, res.Name)
+		if res.Status != "skipped" {
+			line += fmt.Sprintf(" %8s", res.Duration.Round(100*time.Millisecond))
+		}
+		if res.Err != nil {
+			line += "  " + res.Err.Error()
+		}
+		fmt.Println(line)
+	}
+}
+
+// oddsAPIKey reads the odds API key from env (Azure) or the Docker secret file (Compose)
+func oddsAPIKey() string {
+	for _, name := range []string{"ODDS_API_KEY", "THE_ODDS_API_KEY"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	if data, err := os.ReadFile("/run/secrets/odds_api_key"); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return ""
+}
+
+func envOr(name, defaultVal string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return defaultVal
+}
+
+func envSeconds(name string, defaultVal int) time.Duration {
+	if v := os.Getenv(name); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return time.Duration(defaultVal) * time.Second
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeRunner records stage order and fails the stages named in fail
+type fakeRunner struct {
+	fail map[string]bool
+	ran  []string
+}
+
+func (f *fakeRunner) Run(_ context.Context, stage Stage) error {
+	f.ran = append(f.ran, stage.Name)
+	if f.fail[stage.Name] {
+		return errors.New("exited with code 2")
+	}
+	return nil
+}
+
+func TestRunStages(t *testing.T) {
+	stages := []Stage{{Name: "ratings"}, {Name: "odds"}, {Name: "predict"}, {Name: "settle"}}
+
+	cases := []struct {
+		name            string
+		fail            []string
+		skip            []string
+		continueOnError bool
+		wantRan         []string
+		wantStatus      []string
+		wantExit        int
+	}{
+		{
+			name:       "all ok",
+			wantRan:    []string{"ratings", "odds", "predict", "settle"},
+			wantStatus: []string{"ok", "ok", "ok", "ok"},
+		},
+		{
+			name:       "skip odds",
+			skip:       []string{"odds"},
+			wantRan:    []string{"ratings", "predict", "settle"},
+			wantStatus: []string{"ok", "skipped", "ok", "ok"},
+		},
+		{
+			name:       "failure stops the pipeline",
+			fail:       []string{"odds"},
+			wantRan:    []string{"ratings", "odds"},
+			wantStatus: []string{"ok", "failed", "skipped", "skipped"},
+			wantExit:   1,
+		},
+		{
+			name:            "continue on error still exits non-zero",
+			fail:            []string{"odds"},
+			continueOnError: true,
+			wantRan:         []string{"ratings", "odds", "predict", "settle"},
+			wantStatus:      []string{"ok", "failed", "ok", "ok"},
+			wantExit:        1,
+		},
+		{
+			name:       "skipped stages do not fail the run",
+			fail:       []string{"settle"},
+			skip:       []string{"settle"},
+			wantRan:    []string{"ratings", "odds", "predict"},
+			wantStatus: []string{"ok", "ok", "ok", "skipped"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			runner := &fakeRunner{fail: make(map[string]bool)}
+			for _, name := range c.fail {
+				runner.fail[name] = true
+			}
+			skip := make(map[string]bool)
+			for _, name := range c.skip {
+				skip[name] = true
+			}
+
+			results := runStages(context.Background(), zap.NewNop(), runner, stages, skip, c.continueOnError)
+
+			if !reflect.DeepEqual(runner.ran, c.wantRan) {
+				t.Errorf("ran %v, want %v", runner.ran, c.wantRan)
+			}
+			var status []string
+			for _, res := range results {
+				status = append(status, res.Status)
+			}
+			if !reflect.DeepEqual(status, c.wantStatus) {
+				t.Errorf("status %v, want %v", status, c.wantStatus)
+			}
+			if got := exitCode(results); got != c.wantExit {
+				t.Errorf("exit code %d, want %d", got, c.wantExit)
+			}
+		})
+	}
+}
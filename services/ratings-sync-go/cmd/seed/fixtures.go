@@ -0,0 +1,40 @@
+package main
+
+// fixtureTeam is a seed team with baseline Barttorvik-style ratings.
+// Names match canonical/barttorvik names from migration 028 so seeded rows
+// join to the real team registry when it is present.
+type fixtureTeam struct {
+	Name     string
+	Conf     string
+	AdjOE    float64
+	AdjDE    float64
+	AdjTempo float64
+}
+
+// fixtureTeams is the fixed 20-team universe used by cmd/seed.
+// Ratings are realistic mid-season values; daily snapshots drift around them.
+var fixtureTeams = []fixtureTeam{
+	{"Duke", "ACC", 122.4, 91.8, 66.9},
+	{"North Carolina", "ACC", 115.2, 97.3, 71.8},
+	{"Kansas", "Big 12", 114.6, 93.1, 68.2},
+	{"Kentucky", "SEC", 118.9, 98.4, 70.5},
+	{"Gonzaga", "WCC", 121.0, 96.7, 70.9},
+	{"Houston", "Big 12", 117.8, 88.6, 62.4},
+	{"Purdue", "Big Ten", 120.3, 97.9, 65.1},
+	{"Auburn", "SEC", 123.1, 94.2, 68.7},
+	{"Alabama", "SEC", 119.7, 97.5, 73.6},
+	{"Tennessee", "SEC", 114.9, 89.9, 64.8},
+	{"Arizona", "Big 12", 117.5, 95.1, 70.2},
+	{"Connecticut", "Big East", 119.1, 97.0, 64.3},
+	{"Baylor", "Big 12", 118.2, 99.6, 64.9},
+	{"Iowa St.", "Big 12", 116.4, 92.7, 67.5},
+	{"Marquette", "Big East", 114.3, 95.8, 68.0},
+	{"Creighton", "Big East", 113.6, 97.2, 66.1},
+	{"Michigan St.", "Big Ten", 113.8, 92.9, 67.7},
+	{"Texas Tech", "Big 12", 119.4, 96.6, 65.4},
+	{"Illinois", "Big Ten", 117.1, 95.4, 70.8},
+	{"Wisconsin", "Big Ten", 118.6, 98.1, 66.6},
+}
+
+// fixtureBooks are the bookmakers written for each seeded game
+var fixtureBooks = []string{"draftkings", "fanduel", "pinnacle"}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// generate runs the full fixture generator the way main does
+func generate(seed int64, start, now time.Time) ([]seedGame, [][]oddsSnapshot) {
+	games := generateGames(rand.New(rand.NewSource(seed)), start, 14, 4, now)
+	odds := make([][]oddsSnapshot, len(games))
+	for i, g := range games {
+		odds[i] = generateOdds(g)
+	}
+	return games, odds
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 7)
+
+	games1, odds1 := generate(42, start, now)
+	games2, odds2 := generate(42, start, now)
+
+	if !reflect.DeepEqual(games1, games2) {
+		t.Fatal("games differ between runs with the same --start/--seed")
+	}
+	if !reflect.DeepEqual(odds1, odds2) {
+		t.Fatal("odds differ between runs with the same --start/--seed")
+	}
+
+	final := 0
+	for i, g := range games1 {
+		if g.Final != g.CommenceTime.Before(now) {
+			t.Errorf("%s: Final=%v with commence %s and now %s", g.ExternalID, g.Final, g.CommenceTime, now)
+		}
+		if g.Final {
+			final++
+		}
+		for _, o := range odds1[i] {
+			if lead := g.CommenceTime.Sub(o.Time); lead != oddsOpenLead && lead != oddsLatestLead {
+				t.Errorf("%s: snapshot %s is %s before tip-off, want a fixed lead", g.ExternalID, o.Time, lead)
+			}
+		}
+	}
+	if final == 0 || final == len(games1) {
+		t.Errorf("%d of %d games final, want a mix of final and scheduled", final, len(games1))
+	}
+}
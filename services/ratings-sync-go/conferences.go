@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ConferenceMetrics is one conference's aggregate strength on a ratings date
+type ConferenceMetrics struct {
+	Conference    string
+	TeamCount     int
+	AvgAdjO       float64
+	AvgAdjD       float64
+	AvgNet        float64
+	AvgTempo      float64
+	AvgBarthag    float64
+	NetVsNation   float64 // AvgNet minus the national (all-team) average net rating
+	StrengthRank  int     // 1 = strongest conference by AvgNet
+	Wins          int     // Sum of member teams' season wins
+	Losses        int
+	NonConfWins   int // Completed games against teams from other conferences
+	NonConfLosses int
+}
+
+// confTeamRating is a single team's inputs to the conference aggregates
+type confTeamRating struct {
+	Conference string
+	AdjO       float64
+	AdjD       float64
+	Tempo      float64
+	Barthag    float64
+	Wins       int
+	Losses     int
+}
+
+// aggregateConferences averages team ratings by conference and ranks conferences
+// by average net rating. The national average is over teams, not conferences, so
+// a conference's NetVsNation is how far its typical team sits from a typical D1 team.
+func aggregateConferences(teams []confTeamRating) []ConferenceMetrics {
+	byConf := make(map[string]*ConferenceMetrics)
+	nationalNet := 0.0
+	for _, t := range teams {
+		m, ok := byConf[t.Conference]
+		if !ok {
+			m = &ConferenceMetrics{Conference: t.Conference}
+			byConf[t.Conference] = m
+		}
+		m.TeamCount++
+		m.AvgAdjO += t.AdjO
+		m.AvgAdjD += t.AdjD
+		m.AvgNet += t.AdjO - t.AdjD
+		m.AvgTempo += t.Tempo
+		m.AvgBarthag += t.Barthag
+		m.Wins += t.Wins
+		m.Losses += t.Losses
+		nationalNet += t.AdjO - t.AdjD
+	}
+	if len(teams) == 0 {
+		return nil
+	}
+	nationalNet /= float64(len(teams))
+
+	metrics := make([]ConferenceMetrics, 0, len(byConf))
+	for _, m := range byConf {
+		n := float64(m.TeamCount)
+		m.AvgAdjO /= n
+		m.AvgAdjD /= n
+		m.AvgNet /= n
+		m.AvgTempo /= n
+		m.AvgBarthag /= n
+		m.NetVsNation = m.AvgNet - nationalNet
+		metrics = append(metrics, *m)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].AvgNet != metrics[j].AvgNet {
+			return metrics[i].AvgNet > metrics[j].AvgNet
+		}
+		return metrics[i].Conference < metrics[j].Conference
+	})
+	for i := range metrics {
+		metrics[i].StrengthRank = i + 1
+	}
+	return metrics
+}
+
+// ComputeConferenceMetrics aggregates the asOf ratings snapshot by conference,
+// adds each conference's non-conference record for the season, and upserts the
+// result into conference_metrics.
+func (r *RatingsSync) ComputeConferenceMetrics(ctx context.Context, asOf time.Time) error {
+	asOfDate := asOf.UTC().Format("2006-01-02")
+	seasonStart, seasonEnd := seasonWindow(r.config.Season)
+	cutoff := asOf.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if cutoff.Before(seasonEnd) {
+		seasonEnd = cutoff
+	}
+
+	// Prefer the conference Barttorvik reported with this snapshot; teams.conference
+	// is only backfilled once and can lag realignment
+	confByTeam := make(map[string]string)
+	var teams []confTeamRating
+	rows, err := r.db.Query(ctx, `
+		SELECT tr.team_id::text,
+		       COALESCE(NULLIF(tr.raw_barttorvik->>'conf', ''), t.conference),
+		       tr.adj_o, tr.adj_d, tr.tempo, COALESCE(tr.barthag, 0),
+		       COALESCE(tr.wins, 0), COALESCE(tr.losses, 0)
+		FROM team_ratings tr
+		JOIN teams t ON t.id = tr.team_id
+		WHERE tr.rating_date = $1
+		  AND COALESCE(NULLIF(tr.raw_barttorvik->>'conf', ''), t.conference) IS NOT NULL
+	`, asOfDate)
+	if err != nil {
+		return fmt.Errorf("loading ratings: %w", err)
+	}
+	for rows.Next() {
+		var teamID string
+		var t confTeamRating
+		if err := rows.Scan(&teamID, &t.Conference, &t.AdjO, &t.AdjD, &t.Tempo, &t.Barthag, &t.Wins, &t.Losses); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning rating: %w", err)
+		}
+		confByTeam[teamID] = t.Conference
+		teams = append(teams, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading ratings: %w", err)
+	}
+
+	metrics := aggregateConferences(teams)
+	if len(metrics) == 0 {
+		r.logger.Warn("No rated teams for conference metrics", zap.String("date", asOfDate))
+		return nil
+	}
+
+	index := make(map[string]*ConferenceMetrics, len(metrics))
+	for i := range metrics {
+		index[metrics[i].Conference] = &metrics[i]
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT home_team_id::text, away_team_id::text, home_score, away_score
+		FROM games
+		WHERE status IN ('completed', 'final')
+		  AND home_score IS NOT NULL AND away_score IS NOT NULL
+		  AND commence_time >= $1 AND commence_time < $2
+	`, seasonStart, seasonEnd)
+	if err != nil {
+		return fmt.Errorf("loading games: %w", err)
+	}
+	games := 0
+	for rows.Next() {
+		var homeID, awayID string
+		var homeScore, awayScore int
+		if err := rows.Scan(&homeID, &awayID, &homeScore, &awayScore); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning game: %w", err)
+		}
+		homeConf, okHome := confByTeam[homeID]
+		awayConf, okAway := confByTeam[awayID]
+		// Games against unrated (non-D1) opponents don't measure conference strength
+		if !okHome || !okAway || homeConf == awayConf || homeScore == awayScore {
+			continue
+		}
+		winner, loser := index[homeConf], index[awayConf]
+		if awayScore > homeScore {
+			winner, loser = loser, winner
+		}
+		winner.NonConfWins++
+		loser.NonConfLosses++
+		games++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading games: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, m := range metrics {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO conference_metrics (
+				conference, season, as_of_date, team_count,
+				avg_adj_o, avg_adj_d, avg_net_rating, avg_tempo, avg_barthag,
+				net_vs_national, strength_rank,
+				wins, losses, nonconf_wins, nonconf_losses
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			ON CONFLICT (conference, season, as_of_date) DO UPDATE SET
+				team_count = EXCLUDED.team_count,
+				avg_adj_o = EXCLUDED.avg_adj_o,
+				avg_adj_d = EXCLUDED.avg_adj_d,
+				avg_net_rating = EXCLUDED.avg_net_rating,
+				avg_tempo = EXCLUDED.avg_tempo,
+				avg_barthag = EXCLUDED.avg_barthag,
+				net_vs_national = EXCLUDED.net_vs_national,
+				strength_rank = EXCLUDED.strength_rank,
+				wins = EXCLUDED.wins,
+				losses = EXCLUDED.losses,
+				nonconf_wins = EXCLUDED.nonconf_wins,
+				nonconf_losses = EXCLUDED.nonconf_losses
+		`, m.Conference, r.config.Season, asOfDate, m.TeamCount,
+			m.AvgAdjO, m.AvgAdjD, m.AvgNet, m.AvgTempo, m.AvgBarthag,
+			m.NetVsNation, m.StrengthRank,
+			m.Wins, m.Losses, m.NonConfWins, m.NonConfLosses)
+		if err != nil {
+			return fmt.Errorf("storing conference metrics for %s: %w", m.Conference, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	r.logger.Info("Stored conference metrics",
+		zap.String("date", asOfDate),
+		zap.Int("conferences", len(metrics)),
+		zap.Int("nonconf_games", games))
+	return nil
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Deployment profiles. Each one fixes where the database credentials come from
+// and which connection settings are acceptable.
+const (
+	ProfileLocal   = "local"   // Developer machine: DATABASE_URL env, SSL optional
+	ProfileCompose = "compose" // Docker Compose: URL built from /run/secrets/db_password
+	ProfileAzure   = "azure"   // Azure Container Apps: DATABASE_URL env, SSL required
+)
+
+// dbPasswordSecret is the Docker Compose secret mount for the database password
+const dbPasswordSecret = "/run/secrets/db_password"
+
+// resolveProfile returns the explicit profile, else CONFIG_PROFILE, else one
+// inferred from DATABASE_URL.
+func resolveProfile(explicit string) (string, error) {
+	profile := strings.ToLower(strings.TrimSpace(explicit))
+	if profile == "" {
+		profile = strings.ToLower(strings.TrimSpace(os.Getenv("CONFIG_PROFILE")))
+	}
+	if profile == "" {
+		return inferProfile(os.Getenv("DATABASE_URL")), nil
+	}
+	switch profile {
+	case ProfileLocal, ProfileCompose, ProfileAzure:
+		return profile, nil
+	}
+	return "", fmt.Errorf("unknown config profile %q (want %s, %s, or %s)", profile, ProfileLocal, ProfileCompose, ProfileAzure)
+}
+
+// inferProfile guesses the profile for deployments that predate CONFIG_PROFILE.
+// No DATABASE_URL means Compose secrets; run_today.py inside Compose passes a URL
+// for host "postgres"; Azure URLs always carry sslmode=require (azure/main.bicep).
+func inferProfile(databaseURL string) string {
+	if databaseURL == "" {
+		return ProfileCompose
+	}
+	conn, err := parseConnString(databaseURL)
+	if err != nil {
+		return ProfileLocal
+	}
+	switch {
+	case conn.host == "postgres":
+		return ProfileCompose
+	case conn.tlsRequired:
+		return ProfileAzure
+	}
+	return ProfileLocal
+}
+
+// connInfo is what profile checks need from a connection string
+type connInfo struct {
+	host        string
+	hasPassword bool
+	tlsRequired bool // sslmode=require, verify-ca, or verify-full
+}
+
+// parseConnString parses DATABASE_URL the way pgx will when connecting, so both
+// URLs (postgres://...) and keyword/value strings (host=... dbname=...) are
+// accepted. PG* environment variables apply, as they do for pgx.
+func parseConnString(raw string) (connInfo, error) {
+	if scheme, _, found := strings.Cut(raw, "://"); found && scheme != "postgres" && scheme != "postgresql" {
+		return connInfo{}, fmt.Errorf("DATABASE_URL scheme %q must be postgres or postgresql", scheme)
+	}
+	cfg, err := pgconn.ParseConfig(raw)
+	if err != nil {
+		return connInfo{}, fmt.Errorf("DATABASE_URL does not parse: %v", err)
+	}
+
+	// sslmode=prefer/allow fall back to plaintext; disable has no TLS at all
+	tlsRequired := cfg.TLSConfig != nil
+	for _, fb := range cfg.Fallbacks {
+		if fb.TLSConfig == nil {
+			tlsRequired = false
+		}
+	}
+	return connInfo{host: cfg.Host, hasPassword: cfg.Password != "", tlsRequired: tlsRequired}, nil
+}
+
+// readSecret reads a Docker secret file, rejecting missing or empty secrets
+func readSecret(filePath string, secretName string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("secret file not found: %s (%s); container must have secrets mounted", filePath, secretName)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("secret file %s is empty (%s)", filePath, secretName)
+	}
+	return secret, nil
+}
+
+// loadConfig builds Config from the environment for the given profile. A missing
+// Compose secret is returned as an error alongside the otherwise-complete config.
+// Malformed numeric overrides are ignored (defaults kept), as before; Validate
+// reports them.
+func loadConfig(profile string) (Config, error) {
+	// Sport-parameterized database configuration (enables multi-sport deployment)
+	sport := envOr("SPORT", "ncaam")
+	dbUser := envOr("DB_USER", sport)
+	dbName := envOr("DB_NAME", sport)
+	dbHost := envOr("DB_HOST", "postgres")
+	dbPort := envOr("DB_PORT", "5432")
+
+	// - Docker Compose: DATABASE_URL is not set; we build it from /run/secrets/db_password
+	// - Azure Container Apps / local: DATABASE_URL is set via env vars; no /run/secrets mount exists
+	databaseURL := os.Getenv("DATABASE_URL")
+	var secretErr error
+	if databaseURL == "" && profile == ProfileCompose {
+		// NO .env fallback: the password MUST come from the Docker secret file
+		var dbPassword string
+		dbPassword, secretErr = readSecret(dbPasswordSecret, "db_password")
+		if secretErr == nil {
+			databaseURL = fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", dbUser, dbPassword, dbHost, dbPort, dbName)
+		}
+	}
+
+	config := Config{
+		Profile:     profile,
+		DatabaseURL: databaseURL,
+		Season:      getCurrentSeason(),
+		// MANUAL-ONLY: Default to run once and exit (no cron automation)
+		// User triggers via run_today.py when they want fresh picks
+		// Case-insensitive check to match Rust service behavior
+		RunOnce:      strings.ToLower(os.Getenv("RUN_ONCE")) != "false", // Default true
+		BackfillFrom: 0,
+		BackfillTo:   0,
+		// Team matching guardrails (aligned with Rust odds-ingestion service)
+		StrictTeamMatching: strings.ToLower(os.Getenv("STRICT_TEAM_MATCHING")) != "false", // Default true
+		AllowTeamCreation:  strings.ToLower(os.Getenv("ALLOW_TEAM_CREATION")) == "true",   // Default false
+		// Partial-failure thresholds (see failures.go for exit codes)
+		MaxSkippedPct:  10.0,
+		FailOnZeroRows: strings.ToLower(os.Getenv("FAIL_ON_ZERO_ROWS")) != "false", // Default true
+		StrictParsing:  strings.ToLower(os.Getenv("STRICT_PARSING")) != "false",    // Default true
+		// Partial-snapshot guard (see snapshot.go)
+		MinSnapshotPct:    95.0,
+		PartialRetries:    2,
+		PartialRetryDelay: 2 * time.Minute,
+		ChangeThreshold:   0.5,
+		OutageThreshold:   2,
+	}
+
+	if v := os.Getenv("MAX_SKIPPED_PCT"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			config.MaxSkippedPct = parsed
+		}
+	}
+	if v := os.Getenv("MIN_SNAPSHOT_PCT"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			config.MinSnapshotPct = parsed
+		}
+	}
+	if v := os.Getenv("RATINGS_CHANGE_THRESHOLD"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 0 {
+			config.ChangeThreshold = parsed
+		}
+	}
+	if v := os.Getenv("OUTAGE_FAILURE_THRESHOLD"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 1 {
+			config.OutageThreshold = parsed
+		}
+	}
+	if v := os.Getenv("PARTIAL_SNAPSHOT_RETRIES"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			config.PartialRetries = parsed
+		}
+	}
+	if v := os.Getenv("PARTIAL_SNAPSHOT_RETRY_SECONDS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			config.PartialRetryDelay = time.Duration(parsed) * time.Second
+		}
+	}
+
+	// Override season if provided
+	if s := os.Getenv("SEASON"); s != "" {
+		if parsed, err := strconv.Atoi(s); err == nil {
+			config.Season = parsed
+		}
+	}
+
+	// Optional backfill range: BACKFILL_SEASONS="2024-2026" or "2024"
+	if bf := os.Getenv("BACKFILL_SEASONS"); bf != "" {
+		parts := strings.Split(bf, "-")
+		if len(parts) == 2 {
+			if from, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
+				config.BackfillFrom = from
+			}
+			if to, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+				config.BackfillTo = to
+			}
+		} else if len(parts) == 1 {
+			if yr, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
+				config.BackfillFrom = yr
+				config.BackfillTo = yr
+			}
+		}
+	}
+
+	// The rest of the config is still returned so `config print` can show it
+	return config, secretErr
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// numericEnv lists the numeric overrides loadConfig silently ignores when malformed
+var numericEnv = []struct {
+	key   string
+	isInt bool
+}{
+	{"SEASON", true},
+	{"MAX_SKIPPED_PCT", false},
+	{"MIN_SNAPSHOT_PCT", false},
+	{"RATINGS_CHANGE_THRESHOLD", false},
+	{"PARTIAL_SNAPSHOT_RETRIES", true},
+	{"PARTIAL_SNAPSHOT_RETRY_SECONDS", true},
+	{"OUTAGE_FAILURE_THRESHOLD", true},
+}
+
+// Validate reports every problem with c for its profile, so a misconfigured
+// deployment is caught by `config validate` instead of mid-sync.
+func (c Config) Validate() []error {
+	var errs []error
+
+	for _, e := range numericEnv {
+		v := os.Getenv(e.key)
+		if v == "" {
+			continue
+		}
+		var err error
+		if e.isInt {
+			_, err = strconv.Atoi(v)
+		} else {
+			_, err = strconv.ParseFloat(v, 64)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q is not a number (default used)", e.key, v))
+		}
+	}
+	if bf := os.Getenv("BACKFILL_SEASONS"); bf != "" && (c.BackfillFrom == 0 || c.BackfillTo == 0) {
+		errs = append(errs, fmt.Errorf("BACKFILL_SEASONS=%q is not a season or range like 2020-2026", bf))
+	}
+
+	if c.DatabaseURL == "" {
+		// compose builds the URL from the secret; loadConfig reports that failure
+		if c.Profile != ProfileCompose {
+			errs = append(errs, fmt.Errorf("DATABASE_URL is not set (required for the %s profile)", c.Profile))
+		}
+	} else if err := validateDatabaseURL(c.Profile, c.DatabaseURL); err != nil {
+		errs = append(errs, err)
+	}
+
+	if !c.RunOnce {
+		errs = append(errs, errors.New("RUN_ONCE=false is not supported; this service is manual-only"))
+	}
+	if c.Season < 2008 || c.Season > getCurrentSeason()+1 {
+		errs = append(errs, fmt.Errorf("SEASON=%d is outside Barttorvik coverage (2008-%d)", c.Season, getCurrentSeason()+1))
+	}
+	if c.MaxSkippedPct < 0 || c.MaxSkippedPct > 100 {
+		errs = append(errs, fmt.Errorf("MAX_SKIPPED_PCT=%.1f must be between 0 and 100", c.MaxSkippedPct))
+	}
+	if c.MinSnapshotPct < 0 || c.MinSnapshotPct > 100 {
+		errs = append(errs, fmt.Errorf("MIN_SNAPSHOT_PCT=%.1f must be between 0 and 100", c.MinSnapshotPct))
+	}
+	if c.AllowTeamCreation && c.Profile == ProfileAzure {
+		errs = append(errs, errors.New("ALLOW_TEAM_CREATION=true is for controlled local backfills, not the azure profile"))
+	}
+	return errs
+}
+
+// validateDatabaseURL checks the connection string against the profile's expectations
+func validateDatabaseURL(profile, raw string) error {
+	conn, err := parseConnString(raw)
+	if err != nil {
+		return err
+	}
+	if !conn.hasPassword && profile != ProfileLocal {
+		return errors.New("DATABASE_URL has no password")
+	}
+
+	switch profile {
+	case ProfileAzure:
+		if !conn.tlsRequired {
+			return errors.New("azure profile requires sslmode=require (or verify-ca/verify-full)")
+		}
+		if conn.host == "localhost" || conn.host == "postgres" || strings.HasPrefix(conn.host, "/") || net.ParseIP(conn.host).IsLoopback() {
+			return fmt.Errorf("azure profile DATABASE_URL points at %q, not an Azure server", conn.host)
+		}
+	case ProfileCompose:
+		if conn.tlsRequired {
+			return errors.New("compose profile Postgres has no TLS; sslmode=require/verify-ca/verify-full will fail to connect")
+		}
+	}
+	return nil
+}
+
+// dsnPassword matches the password in a keyword/value connection string
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(?:\\.|[^'])*'|\S+)`)
+
+// redactConnString masks the password in either connection string form
+func redactConnString(raw string) string {
+	if !strings.Contains(raw, "://") {
+		return dsnPassword.ReplaceAllString(raw, "${1}xxxxx")
+	}
+	if u, err := url.Parse(raw); err == nil {
+		return u.Redacted()
+	}
+	return "<unparseable, redacted>"
+}
+
+// Print writes the effective configuration, one key per line. With redact, the
+// database password is masked so the output is safe to paste into tickets/logs.
+func (c Config) Print(w io.Writer, redact bool) {
+	dbURL := c.DatabaseURL
+	if redact && dbURL != "" {
+		dbURL = redactConnString(dbURL)
+	}
+	backfill := "off"
+	if c.BackfillFrom != 0 && c.BackfillTo != 0 {
+		backfill = fmt.Sprintf("%d-%d", c.BackfillFrom, c.BackfillTo)
+	}
+
+	fmt.Fprintf(w, "profile=%s\n", c.Profile)
+	fmt.Fprintf(w, "database_url=%s\n", dbURL)
+	fmt.Fprintf(w, "season=%d\n", c.Season)
+	fmt.Fprintf(w, "run_once=%t\n", c.RunOnce)
+	fmt.Fprintf(w, "backfill_seasons=%s\n", backfill)
+	fmt.Fprintf(w, "strict_team_matching=%t\n", c.StrictTeamMatching)
+	fmt.Fprintf(w, "allow_team_creation=%t\n", c.AllowTeamCreation)
+	fmt.Fprintf(w, "strict_parsing=%t\n", c.StrictParsing)
+	fmt.Fprintf(w, "max_skipped_pct=%.1f\n", c.MaxSkippedPct)
+	fmt.Fprintf(w, "fail_on_zero_rows=%t\n", c.FailOnZeroRows)
+	fmt.Fprintf(w, "min_snapshot_pct=%.1f\n", c.MinSnapshotPct)
+	fmt.Fprintf(w, "partial_snapshot_retries=%d\n", c.PartialRetries)
+	fmt.Fprintf(w, "partial_snapshot_retry_delay=%s\n", c.PartialRetryDelay)
+	fmt.Fprintf(w, "ratings_change_threshold=%.2f\n", c.ChangeThreshold)
+	fmt.Fprintf(w, "outage_failure_threshold=%d\n", c.OutageThreshold)
+}
+
+// runConfigCommand implements `ratings-sync config validate|print [--redacted]`
+// and returns the process exit code.
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	usage := "usage: ratings-sync config validate|print [--profile local|compose|azure] [--redacted=true]"
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return ExitFatal
+	}
+
+	fs := flag.NewFlagSet("config "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	profileFlag := fs.String("profile", "", "config profile (default: CONFIG_PROFILE, else inferred)")
+	redact := fs.Bool("redacted", true, "mask secrets in printed output")
+	if err := fs.Parse(args[1:]); err != nil {
+		return ExitFatal
+	}
+
+	profile, err := resolveProfile(*profileFlag)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFatal
+	}
+	config, loadErr := loadConfig(profile)
+
+	switch args[0] {
+	case "print":
+		config.Print(stdout, *redact)
+		if loadErr != nil {
+			fmt.Fprintln(stderr, "warning:", loadErr)
+		}
+		return ExitOK
+	case "validate":
+		errs := config.Validate()
+		if loadErr != nil {
+			errs = append([]error{loadErr}, errs...)
+		}
+		if len(errs) == 0 {
+			fmt.Fprintf(stdout, "config OK (profile=%s)\n", profile)
+			return ExitOK
+		}
+		fmt.Fprintf(stderr, "config INVALID (profile=%s):\n", profile)
+		for _, e := range errs {
+			fmt.Fprintf(stderr, "  - %v\n", e)
+		}
+		return ExitFatal
+	}
+	fmt.Fprintln(stderr, usage)
+	return ExitFatal
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ratingsChangedChannel is the LISTEN/NOTIFY channel signalled when a snapshot
+// produces change events. The payload is a summary; consumers read the rows.
+const ratingsChangedChannel = "ratings_changed"
+
+// emitRatingChanges writes a ratings_change_events row for every team whose
+// efficiency or tempo moved more than the configured threshold since the previous
+// ratings day (or that has no previous rating). It runs inside the snapshot
+// transaction so events exist if and only if the ratings they describe commit.
+//
+// Emission is wrapped in a savepoint: if it fails (e.g. migration 032 not applied)
+// the ratings are still committed and the failure is logged.
+func (r *RatingsSync) emitRatingChanges(ctx context.Context, tx pgx.Tx, date string) {
+	if !r.schemaReady("ratings_change_events") {
+		return
+	}
+	prevDate, _, ok, err := previousSnapshot(ctx, tx, date)
+	if err != nil {
+		r.logger.Warn("Skipping ratings change events", zap.Error(err))
+		return
+	}
+	if !ok {
+		r.logger.Info("No previous ratings day; skipping change events", zap.String("date", date))
+		return
+	}
+
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		r.logger.Warn("Skipping ratings change events", zap.Error(err))
+		return
+	}
+	defer sp.Rollback(ctx)
+
+	emitted, err := insertRatingChanges(ctx, sp, date, prevDate, r.config.ChangeThreshold)
+	if err != nil {
+		r.logger.Warn("Failed to emit ratings change events", zap.Error(err))
+		return
+	}
+	if emitted > 0 {
+		payload, _ := json.Marshal(map[string]any{
+			"rating_date":   date,
+			"previous_date": prevDate,
+			"teams":         emitted,
+		})
+		// Delivered to listeners only when the outer transaction commits
+		if _, err := sp.Exec(ctx, `SELECT pg_notify($1, $2)`, ratingsChangedChannel, string(payload)); err != nil {
+			r.logger.Warn("Failed to notify ratings change listeners", zap.Error(err))
+			return
+		}
+	}
+	if err := sp.Commit(ctx); err != nil {
+		r.logger.Warn("Failed to emit ratings change events", zap.Error(err))
+		return
+	}
+
+	r.logger.Info("Emitted ratings change events",
+		zap.String("date", date),
+		zap.String("previous_date", prevDate),
+		zap.Int("teams", emitted),
+		zap.Float64("threshold", r.config.ChangeThreshold))
+}
+
+// insertRatingChanges upserts change events for date vs prevDate and returns how many were written.
+// Re-running a day refreshes the deltas and marks the event unprocessed again.
+func insertRatingChanges(ctx context.Context, tx pgx.Tx, date, prevDate string, threshold float64) (int, error) {
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO ratings_change_events (
+			team_id, rating_date, previous_date,
+			adj_o_delta, adj_d_delta, tempo_delta, net_rating_delta, is_new_team
+		)
+		SELECT cur.team_id, cur.rating_date, $2::date,
+		       cur.adj_o - prev.adj_o,
+		       cur.adj_d - prev.adj_d,
+		       cur.tempo - prev.tempo,
+		       (cur.adj_o - cur.adj_d) - (prev.adj_o - prev.adj_d),
+		       prev.team_id IS NULL
+		FROM team_ratings cur
+		LEFT JOIN team_ratings prev
+		       ON prev.team_id = cur.team_id AND prev.rating_date = $2::date
+		WHERE cur.rating_date = $1::date
+		  AND (
+		        prev.team_id IS NULL
+		     OR ABS(cur.adj_o - prev.adj_o) > $3
+		     OR ABS(cur.adj_d - prev.adj_d) > $3
+		     OR ABS(cur.tempo - prev.tempo) > $3
+		  )
+		ON CONFLICT (team_id, rating_date) DO UPDATE SET
+			previous_date = EXCLUDED.previous_date,
+			adj_o_delta = EXCLUDED.adj_o_delta,
+			adj_d_delta = EXCLUDED.adj_d_delta,
+			tempo_delta = EXCLUDED.tempo_delta,
+			net_rating_delta = EXCLUDED.net_rating_delta,
+			is_new_team = EXCLUDED.is_new_team,
+			processed_at = NULL
+	`, date, prevDate, threshold)
+	if err != nil {
+		return 0, fmt.Errorf("inserting change events: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
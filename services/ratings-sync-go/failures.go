@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Process exit codes. Distinct codes let run_today.py / runtoday and alerting
+// tell a provider outage apart from a data problem or a database failure.
+const (
+	ExitOK                 = 0
+	ExitFatal              = 1 // Configuration or connection errors (logger.Fatal)
+	ExitFetchFailed        = 2 // Barttorvik request, decode, or format check failed
+	ExitNoRows             = 3 // Barttorvik returned zero usable teams
+	ExitSkipThreshold      = 4 // Too many teams skipped (invalid rows + unresolved names)
+	ExitStoreFailed        = 5 // Transaction could not be started or committed
+	ExitBackfillIncomplete = 6 // One or more backfill seasons failed
+	ExitPartialSnapshot    = 7 // Snapshot much smaller than the previous day (not committed)
+)
+
+// SyncError is a sync failure tagged with the exit code it should produce
+type SyncError struct {
+	Code int
+	Err  error
+}
+
+func (e *SyncError) Error() string { return e.Err.Error() }
+
+func (e *SyncError) Unwrap() error { return e.Err }
+
+// exitCodeFor maps an error returned by Sync/Backfill to a process exit code
+func exitCodeFor(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var syncErr *SyncError
+	if errors.As(err, &syncErr) {
+		return syncErr.Code
+	}
+	return ExitFatal
+}
+
+// skippedPct returns the share (0-100) of Barttorvik rows that did not end up stored.
+// parseSkipped rows never became teams; fetched-stored teams failed resolution or insert.
+func skippedPct(parseSkipped, fetched, stored int) float64 {
+	total := parseSkipped + fetched
+	if total == 0 {
+		return 0
+	}
+	return float64(parseSkipped+fetched-stored) / float64(total) * 100
+}
+
+// checkSkipThreshold fails the run when more than MaxSkippedPct of rows were dropped
+func (c Config) checkSkipThreshold(stats SyncStats) error {
+	if c.MaxSkippedPct <= 0 {
+		return nil
+	}
+	pct := skippedPct(stats.ParseSkipped, stats.Fetched, stats.Stored)
+	if pct > c.MaxSkippedPct {
+		return &SyncError{
+			Code: ExitSkipThreshold,
+			Err: fmt.Errorf("%.1f%% of teams skipped (%d invalid, %d not stored) exceeds MAX_SKIPPED_PCT=%.1f",
+				pct, stats.ParseSkipped, stats.Fetched-stats.Stored, c.MaxSkippedPct),
+		}
+	}
+	return nil
+}
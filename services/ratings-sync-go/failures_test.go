@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestFinishSyncRefreshesDerivedOnSkipThreshold(t *testing.T) {
+	r := &RatingsSync{logger: zap.NewNop(), config: Config{MaxSkippedPct: 5}}
+	// 20 of 100 rows not stored: 20% > 5%
+	stats := SyncStats{Fetched: 100, Stored: 80}
+
+	var ran []string
+	step := func(name string, err error) derivedStep {
+		return derivedStep{name: name, run: func(context.Context, time.Time) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+	steps := []derivedStep{
+		step("quadrant records", nil),
+		step("conference metrics", errors.New("boom")),
+		step("season projections", nil),
+	}
+
+	err := r.finishSync(context.Background(), stats, steps, time.Now())
+	if got := exitCodeFor(err); got != ExitSkipThreshold {
+		t.Fatalf("exit code = %d (%v), want %d", got, err, ExitSkipThreshold)
+	}
+	if len(ran) != len(steps) {
+		t.Fatalf("ran %v, want every derived step despite the threshold breach", ran)
+	}
+}
+
+func TestFinishSyncWithinThreshold(t *testing.T) {
+	r := &RatingsSync{logger: zap.NewNop(), config: Config{MaxSkippedPct: 5}}
+	stats := SyncStats{Fetched: 100, Stored: 99}
+
+	ran := 0
+	steps := []derivedStep{{name: "recent form", run: func(context.Context, time.Time) error {
+		ran++
+		return nil
+	}}}
+
+	if err := r.finishSync(context.Background(), stats, steps, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ran != 1 {
+		t.Fatalf("ran %d steps, want 1", ran)
+	}
+}
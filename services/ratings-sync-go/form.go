@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// formWindow is how many of a team's most recent games feed its form metric
+	formWindow = 5
+	// formHomeCourt is the full-game home court edge in points; matches the
+	// prediction service's home_court_advantage_spread default
+	formHomeCourt = 5.8
+)
+
+// gameRatings are a team's efficiency ratings as of a game
+type gameRatings struct {
+	AdjO, AdjD, Tempo float64
+}
+
+// expectedMargin is the margin the ratings at game time predicted for team vs opp.
+// site is +1 home, -1 away, 0 neutral.
+func expectedMargin(team, opp gameRatings, site int) float64 {
+	possessions := (team.Tempo + opp.Tempo) / 2
+	netDiff := (team.AdjO - team.AdjD) - (opp.AdjO - opp.AdjD)
+	return netDiff*possessions/100 + float64(site)*formHomeCourt
+}
+
+// TeamForm is a team's opponent-adjusted form over its last formWindow games
+type TeamForm struct {
+	Games          int
+	AvgMargin      float64 // Raw scoring margin
+	AvgExpected    float64 // Margin predicted by ratings at game time
+	AdjustedMargin float64 // AvgMargin - AvgExpected: points per game better than expected
+	LastGameAt     time.Time
+}
+
+// formGame is one completed game from a team's perspective. Team/Opp are nil
+// when that side had no ratings snapshot before the game.
+type formGame struct {
+	TeamID    string
+	At        time.Time
+	Margin    int
+	Site      int
+	Team, Opp *gameRatings
+}
+
+// buildForms accumulates each team's last window games that have ratings for both
+// sides. games must be ordered by team, most recent first; ineligible games are
+// skipped before counting, so older eligible games fill the window.
+func buildForms(games []formGame, window int) map[string]*TeamForm {
+	forms := make(map[string]*TeamForm)
+	for _, g := range games {
+		if g.Team == nil || g.Opp == nil {
+			continue
+		}
+		f, ok := forms[g.TeamID]
+		if !ok {
+			f = &TeamForm{LastGameAt: g.At}
+			forms[g.TeamID] = f
+		}
+		if f.Games >= window {
+			continue
+		}
+		f.Games++
+		f.AvgMargin += float64(g.Margin)
+		f.AvgExpected += expectedMargin(*g.Team, *g.Opp, g.Site)
+	}
+	for _, f := range forms {
+		n := float64(f.Games)
+		f.AvgMargin /= n
+		f.AvgExpected /= n
+		f.AdjustedMargin = f.AvgMargin - f.AvgExpected
+	}
+	return forms
+}
+
+// ComputeRecentForm computes opponent-adjusted last-5 form for every team with
+// completed games this season and upserts it into team_recent_form.
+//
+// Each game's expected margin uses both teams' most recent ratings snapshot from
+// before the game's Eastern date, so form reflects how good the opponent looked
+// then, not now. A same-day snapshot is excluded: it may already include the
+// game's result. Games where either side has no snapshot yet (non-D1 opponents,
+// games before a team's first snapshot) are skipped and older games fill the window.
+func (r *RatingsSync) ComputeRecentForm(ctx context.Context, asOf time.Time) error {
+	asOfDate := asOf.UTC().Format("2006-01-02")
+	seasonStart, seasonEnd := seasonWindow(r.config.Season)
+	cutoff := asOf.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if cutoff.Before(seasonEnd) {
+		seasonEnd = cutoff
+	}
+
+	rows, err := r.db.Query(ctx, `
+		WITH team_games AS (
+			SELECT home_team_id AS team_id, away_team_id AS opp_id, commence_time,
+			       home_score - away_score AS margin,
+			       CASE WHEN COALESCE(is_neutral, FALSE) THEN 0 ELSE 1 END AS site
+			FROM games
+			WHERE status IN ('completed', 'final')
+			  AND home_score IS NOT NULL AND away_score IS NOT NULL
+			  AND commence_time >= $1 AND commence_time < $2
+			UNION ALL
+			SELECT away_team_id, home_team_id, commence_time,
+			       away_score - home_score,
+			       CASE WHEN COALESCE(is_neutral, FALSE) THEN 0 ELSE -1 END
+			FROM games
+			WHERE status IN ('completed', 'final')
+			  AND home_score IS NOT NULL AND away_score IS NOT NULL
+			  AND commence_time >= $1 AND commence_time < $2
+		)
+		SELECT tg.team_id::text, tg.commence_time, tg.margin, tg.site,
+		       tr.adj_o, tr.adj_d, tr.tempo,
+		       ot.adj_o, ot.adj_d, ot.tempo
+		FROM team_games tg
+		LEFT JOIN LATERAL (
+			SELECT adj_o, adj_d, tempo FROM team_ratings
+			WHERE team_id = tg.team_id
+			  AND rating_date < (tg.commence_time AT TIME ZONE 'America/New_York')::date
+			  AND adj_o IS NOT NULL AND adj_d IS NOT NULL AND tempo IS NOT NULL
+			ORDER BY rating_date DESC LIMIT 1
+		) tr ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT adj_o, adj_d, tempo FROM team_ratings
+			WHERE team_id = tg.opp_id
+			  AND rating_date < (tg.commence_time AT TIME ZONE 'America/New_York')::date
+			  AND adj_o IS NOT NULL AND adj_d IS NOT NULL AND tempo IS NOT NULL
+			ORDER BY rating_date DESC LIMIT 1
+		) ot ON TRUE
+		ORDER BY tg.team_id, tg.commence_time DESC
+	`, seasonStart, seasonEnd)
+	if err != nil {
+		return fmt.Errorf("loading recent games: %w", err)
+	}
+
+	var games []formGame
+	for rows.Next() {
+		var g formGame
+		var teamO, teamD, teamT, oppO, oppD, oppT *float64
+		if err := rows.Scan(&g.TeamID, &g.At, &g.Margin, &g.Site,
+			&teamO, &teamD, &teamT, &oppO, &oppD, &oppT); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning game: %w", err)
+		}
+		if teamO != nil {
+			g.Team = &gameRatings{AdjO: *teamO, AdjD: *teamD, Tempo: *teamT}
+		}
+		if oppO != nil {
+			g.Opp = &gameRatings{AdjO: *oppO, AdjD: *oppD, Tempo: *oppT}
+		}
+		games = append(games, g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading recent games: %w", err)
+	}
+
+	forms := buildForms(games, formWindow)
+	if len(forms) == 0 {
+		r.logger.Warn("No completed games for recent form", zap.String("date", asOfDate))
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for teamID, f := range forms {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO team_recent_form (
+				team_id, season, as_of_date, window_games, games_used,
+				avg_margin, avg_expected_margin, adjusted_margin, last_game_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (team_id, season, as_of_date) DO UPDATE SET
+				window_games = EXCLUDED.window_games,
+				games_used = EXCLUDED.games_used,
+				avg_margin = EXCLUDED.avg_margin,
+				avg_expected_margin = EXCLUDED.avg_expected_margin,
+				adjusted_margin = EXCLUDED.adjusted_margin,
+				last_game_at = EXCLUDED.last_game_at
+		`, teamID, r.config.Season, asOfDate, formWindow, f.Games,
+			f.AvgMargin, f.AvgExpected, f.AdjustedMargin, f.LastGameAt)
+		if err != nil {
+			return fmt.Errorf("storing recent form: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	r.logger.Info("Stored recent form",
+		zap.String("date", asOfDate),
+		zap.Int("teams", len(forms)),
+		zap.Int("window", formWindow))
+	return nil
+}
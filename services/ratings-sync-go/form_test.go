@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildFormsBackfillsSkippedGames(t *testing.T) {
+	rated := &gameRatings{AdjO: 110, AdjD: 100, Tempo: 70}
+	base := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// Seven games, most recent first. The two most recent opponents have no
+	// snapshot yet, so the window must reach back to games 3-7.
+	var games []formGame
+	for i := 0; i < 7; i++ {
+		g := formGame{TeamID: "a", At: base.AddDate(0, 0, -i), Margin: i, Site: 0, Team: rated, Opp: rated}
+		if i < 2 {
+			g.Opp = nil
+		}
+		games = append(games, g)
+	}
+
+	forms := buildForms(games, formWindow)
+	f, ok := forms["a"]
+	if !ok {
+		t.Fatal("no form for team a")
+	}
+	if f.Games != formWindow {
+		t.Fatalf("games_used = %d, want %d", f.Games, formWindow)
+	}
+	// Margins 2..6 average 4; equal ratings on a neutral site expect 0
+	if math.Abs(f.AvgMargin-4) > 1e-9 || math.Abs(f.AdjustedMargin-4) > 1e-9 {
+		t.Fatalf("avg margin %.2f, adjusted %.2f, want 4 and 4", f.AvgMargin, f.AdjustedMargin)
+	}
+	if !f.LastGameAt.Equal(base.AddDate(0, 0, -2)) {
+		t.Fatalf("last game %s, want most recent eligible game", f.LastGameAt)
+	}
+}
+
+func TestBuildFormsShortSeason(t *testing.T) {
+	rated := &gameRatings{AdjO: 105, AdjD: 100, Tempo: 68}
+	games := []formGame{
+		{TeamID: "b", Margin: 10, Site: 1, Team: rated, Opp: rated},
+		{TeamID: "b", Margin: -4, Site: -1, Team: nil, Opp: rated},
+		{TeamID: "b", Margin: 2, Site: -1, Team: rated, Opp: rated},
+	}
+
+	forms := buildForms(games, formWindow)
+	f := forms["b"]
+	if f == nil || f.Games != 2 {
+		t.Fatalf("form = %+v, want 2 eligible games", f)
+	}
+	// Home +5.8 and away -5.8 cancel out
+	if math.Abs(f.AvgExpected) > 1e-9 {
+		t.Fatalf("avg expected %.2f, want 0", f.AvgExpected)
+	}
+}
@@ -8,6 +8,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -23,37 +24,40 @@ import (
 	"go.uber.org/zap"
 )
 
-// BarttorkvikTeam represents a team's data from the Barttorvik JSON API
+// BarttorkvikTeam represents a team's data from the Barttorvik JSON API.
+// Critical efficiency inputs are plain floats (rows without them are rejected);
+// secondary metrics are pointers so a missing value is stored as NULL, not 0.
 type BarttorkvikTeam struct {
-	Team     string  `json:"team"`
-	Conf     string  `json:"conf"`
-	G        int     `json:"g"`
-	Wins     int     `json:"wins"`
-	Losses   int     `json:"losses"`
-	AdjOE    float64 `json:"adjoe"`
-	AdjDE    float64 `json:"adjde"`
-	Barthag  float64 `json:"barthag"`
-	EFG      float64 `json:"efg_o"`
-	EFGD     float64 `json:"efg_d"`
-	TOR      float64 `json:"tor"`
-	TORD     float64 `json:"tord"`
-	ORB      float64 `json:"orb"`
-	DRB      float64 `json:"drb"`
-	FTR      float64 `json:"ftr"`
-	FTRD     float64 `json:"ftrd"`
-	TwoP     float64 `json:"2p_o"`
-	TwoPD    float64 `json:"2p_d"`
-	ThreeP   float64 `json:"3p_o"`
-	ThreePD  float64 `json:"3p_d"`
-	ThreePR  float64 `json:"3pr"`
-	ThreePRD float64 `json:"3prd"`
-	AdjTempo float64 `json:"adj_t"`
-	WAB      float64 `json:"wab"`
-	Rank     int     `json:"rk"`
+	Team     string   `json:"team"`
+	Conf     string   `json:"conf"`
+	G        int      `json:"g"`
+	Wins     int      `json:"wins"`
+	Losses   int      `json:"losses"`
+	AdjOE    float64  `json:"adjoe"`
+	AdjDE    float64  `json:"adjde"`
+	Barthag  float64  `json:"barthag"`
+	EFG      *float64 `json:"efg_o"`
+	EFGD     *float64 `json:"efg_d"`
+	TOR      *float64 `json:"tor"`
+	TORD     *float64 `json:"tord"`
+	ORB      *float64 `json:"orb"`
+	DRB      *float64 `json:"drb"`
+	FTR      *float64 `json:"ftr"`
+	FTRD     *float64 `json:"ftrd"`
+	TwoP     *float64 `json:"2p_o"`
+	TwoPD    *float64 `json:"2p_d"`
+	ThreeP   *float64 `json:"3p_o"`
+	ThreePD  *float64 `json:"3p_d"`
+	ThreePR  *float64 `json:"3pr"`
+	ThreePRD *float64 `json:"3prd"`
+	AdjTempo float64  `json:"adj_t"`
+	WAB      *float64 `json:"wab"` // nil when Barttorvik doesn't report it
+	Rank     int      `json:"rk"`
 }
 
 // Config holds application configuration
 type Config struct {
+	Profile      string // local, compose, or azure (see config.go)
 	DatabaseURL  string
 	Season       int
 	RunOnce      bool
@@ -65,6 +69,27 @@ type Config struct {
 	// If true, allow creating new teams when resolution fails.
 	// Default: false (prevents unrated/duplicate teams from name drift).
 	AllowTeamCreation bool
+	// Fail the run if more than this percent of Barttorvik rows are skipped
+	// (invalid rows + unresolved/failed inserts). 0 disables the check.
+	MaxSkippedPct float64
+	// Fail the run if Barttorvik returns zero usable teams. Default: true.
+	FailOnZeroRows bool
+	// If true, out-of-range critical metrics (tempo, barthag) reject the row
+	// instead of being defaulted, and missing secondary metrics are stored as NULL.
+	// Default: true. Set STRICT_PARSING=false for legacy zero-fill behavior.
+	StrictParsing bool
+	// Abort (roll back) a snapshot with fewer rows than this percent of the previous
+	// ratings day. Default: 95. 0 disables the check.
+	MinSnapshotPct float64
+	// How many times to re-fetch after a partial snapshot, and how long to wait between
+	PartialRetries    int
+	PartialRetryDelay time.Duration
+	// Minimum adj_o/adj_d/tempo move (points) that emits a ratings change event.
+	// Default: 0.5. 0 emits an event for any change.
+	ChangeThreshold float64
+	// Consecutive failed runs before a provider outage is confirmed (counts toward
+	// degraded windows). Default: 2, so a single transient failure is not an outage.
+	OutageThreshold int
 }
 
 // RatingsSync handles fetching and storing ratings
@@ -72,6 +97,10 @@ type RatingsSync struct {
 	db     *pgxpool.Pool
 	logger *zap.Logger
 	config Config
+	// unavailable holds optional tables that failed CheckSchema
+	unavailable map[string]bool
+	// backfilling is set while Backfill drives Sync over past seasons
+	backfilling bool
 }
 
 // NewRatingsSync creates a new sync service
@@ -83,15 +112,16 @@ func NewRatingsSync(db *pgxpool.Pool, logger *zap.Logger, config Config) *Rating
 	}
 }
 
-// FetchRatings fetches current ratings from Barttorvik
-func (r *RatingsSync) FetchRatings(ctx context.Context) ([]BarttorkvikTeam, error) {
+// FetchRatings fetches current ratings from Barttorvik.
+// Also returns a parse report (rows skipped, secondary fields missing).
+func (r *RatingsSync) FetchRatings(ctx context.Context) ([]BarttorkvikTeam, ParseReport, error) {
 	url := fmt.Sprintf("https://barttorvik.com/%d_team_results.json", r.config.Season)
 
 	r.logger.Info("Fetching ratings from Barttorvik", zap.String("url", url))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, ParseReport{}, fmt.Errorf("creating request: %w", err)
 	}
 
 	// Set user agent to avoid blocking
@@ -100,7 +130,10 @@ func (r *RatingsSync) FetchRatings(ctx context.Context) ([]BarttorkvikTeam, erro
 	// Perform request with exponential backoff + jitter for transient failures
 	resp, err := doRequestWithRetry(ctx, req, 5)
 	if err != nil {
-		return nil, err
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, ParseReport{}, err
 	}
 	defer resp.Body.Close()
 
@@ -108,156 +141,21 @@ func (r *RatingsSync) FetchRatings(ctx context.Context) ([]BarttorkvikTeam, erro
 	// Format: [[rank, team, conf, record, adjoe, adjoe_rank, adjde, adjde_rank, ...], ...]
 	var rawTeams [][]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&rawTeams); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
-	}
-
-	// Format validation: check first row structure
-	if len(rawTeams) > 0 {
-		first := rawTeams[0]
-		r.logger.Info("Barttorvik format check",
-			zap.Int("field_count", len(first)),
-			zap.String("sample_team", toString(first[1])),
-		)
-		// Expected: 45 fields for 2025-26. Log warning if format changed.
-		if len(first) < 25 {
-			r.logger.Error("Barttorvik format changed - too few fields",
-				zap.Int("expected_min", 25),
-				zap.Int("actual", len(first)),
-			)
-			return nil, fmt.Errorf("barttorvik format changed: expected >=25 fields, got %d", len(first))
-		}
-		if len(first) < 40 || len(first) > 50 {
-			r.logger.Warn("Barttorvik format may have changed - unusual field count",
-				zap.Int("expected_range", 45),
-				zap.Int("actual", len(first)),
-			)
-		}
+		return nil, ParseReport{}, fmt.Errorf("decoding response: %w", err)
 	}
 
-	var teams []BarttorkvikTeam
-	skipped := 0
-	for _, raw := range rawTeams {
-		// 2025-26 season: Barttorvik returns 45 fields (indices 0-44)
-		// AdjTempo is at index 44 (last element)
-		if len(raw) < 45 {
-			skipped++
-			continue // Skip incomplete records - need all metrics
-		}
-
-		// Direct index mapping based on actual Barttorvik 2025 JSON format:
-		// [0]=rank, [1]=team, [2]=conf, [3]=record, [4]=adjoe, [5]=adjoe_rank,
-		// [6]=adjde, [7]=adjde_rank, [8]=barthag, [9]=barthag_rank,
-		// [10]=wins, [11]=losses, [12]=conf_wins, [13]=conf_losses, [14]=conf_record,
-		// [15]=efg_o, [16]=efg_d, [17]=tor, [18]=tord, [19]=orb_o, [20]=drb_d,
-		// [21]=ftr_o, [22]=ftr_d, [23]=2p_o, [24]=2p_d, [25]=3p_o, [26]=3p_d,
-		// [27]=3pr_o, [28]=3pr_d, [29-43]=various advanced stats,
-		// [44]=adj_tempo (LAST FIELD)
-		dataMap := make(map[string]interface{})
-		dataMap["rank"] = raw[0]
-		dataMap["team"] = raw[1]
-		dataMap["conf"] = raw[2]
-		dataMap["record"] = raw[3]
-		dataMap["adjoe"] = raw[4]
-		dataMap["adjde"] = raw[6]
-		dataMap["barthag"] = raw[8]
-		dataMap["wins"] = raw[10]
-		dataMap["losses"] = raw[11]
-		dataMap["efg"] = raw[15]
-		dataMap["efgd"] = raw[16]
-		dataMap["tor"] = raw[17]
-		dataMap["tord"] = raw[18]
-		dataMap["orb"] = raw[19]
-		dataMap["drb"] = raw[20]
-		dataMap["ftr"] = raw[21]
-		dataMap["ftrd"] = raw[22]
-		dataMap["2p"] = raw[23]
-		dataMap["2pd"] = raw[24]
-		dataMap["3p"] = raw[25]
-		dataMap["3pd"] = raw[26]
-		dataMap["3pr"] = raw[27]
-		dataMap["3prd"] = raw[28]
-		dataMap["adj_t"] = raw[44] // TEMPO IS THE LAST FIELD
-		// WAB doesn't have a consistent position, use default
-		dataMap["wab"] = 0.0
-
-		// Parse wins/losses from the dedicated fields (more reliable than record string)
-		wins := getInt(dataMap, "wins", 0)
-		losses := getInt(dataMap, "losses", 0)
-
-		// Extract with defaults and validation
-		adjTempo := getFloat(dataMap, "adj_t", 70.0)
-		wab := getFloat(dataMap, "wab", 0.0)
-
-		team := BarttorkvikTeam{
-			// Core identifiers
-			Rank: getInt(dataMap, "rank", 0),
-			Team: toString(dataMap["team"]),
-			Conf: toString(dataMap["conf"]),
-
-			// Efficiency ratings (primary prediction inputs)
-			AdjOE:    getFloat(dataMap, "adjoe", 0.0),
-			AdjDE:    getFloat(dataMap, "adjde", 0.0),
-			AdjTempo: adjTempo,
-
-			// Record
-			Wins:   wins,
-			Losses: losses,
-			G:      wins + losses,
-
-			// Quality metrics
-			Barthag: getFloat(dataMap, "barthag", 0.0),
-			WAB:     wab,
-
-			// Four Factors - Shooting
-			EFG:  getFloat(dataMap, "efg", 0.0),
-			EFGD: getFloat(dataMap, "efgd", 0.0),
-
-			// Four Factors - Turnovers
-			TOR:  getFloat(dataMap, "tor", 0.0),
-			TORD: getFloat(dataMap, "tord", 0.0),
-
-			// Four Factors - Rebounding
-			ORB: getFloat(dataMap, "orb", 0.0),
-			DRB: getFloat(dataMap, "drb", 0.0),
-
-			// Four Factors - Free Throws
-			FTR:  getFloat(dataMap, "ftr", 0.0),
-			FTRD: getFloat(dataMap, "ftrd", 0.0),
-
-			// Shooting breakdown (using dataMap which has correct indices)
-			TwoP:     getFloat(dataMap, "2p", 0.0),
-			TwoPD:    getFloat(dataMap, "2pd", 0.0),
-			ThreeP:   getFloat(dataMap, "3p", 0.0),
-			ThreePD:  getFloat(dataMap, "3pd", 0.0),
-			ThreePR:  getFloat(dataMap, "3pr", 0.0),
-			ThreePRD: getFloat(dataMap, "3prd", 0.0),
-		}
-
-		// Validate parsed values are in reasonable ranges
-		if !validateTeamRatings(&team, r.logger) {
-			r.logger.Warn("Skipping team with invalid ratings",
-				zap.String("team", team.Team),
-				zap.Float64("adj_o", team.AdjOE),
-				zap.Float64("adj_d", team.AdjDE),
-			)
-			skipped++
-			continue
-		}
-
-		teams = append(teams, team)
-	}
-
-	if skipped > 0 {
-		r.logger.Warn("Skipped teams with incomplete/invalid data", zap.Int("skipped", skipped))
+	teams, report, err := parseTeams(rawTeams, r.config.StrictParsing, r.logger)
+	if err != nil {
+		return nil, report, err
 	}
 
 	r.logger.Info("Fetched ratings", zap.Int("team_count", len(teams)))
-	return teams, nil
+	return teams, report, nil
 }
 
 // validateTeamRatings checks that parsed ratings are within valid bounds
 // Returns false if critical values are missing or invalid
-func validateTeamRatings(team *BarttorkvikTeam, logger *zap.Logger) bool {
+func validateTeamRatings(team *BarttorkvikTeam, strict bool, logger *zap.Logger) bool {
 	// Efficiency bounds: NCAA D1 teams range roughly 70-140
 	const effMin, effMax = 70.0, 140.0
 	// Tempo bounds: slowest ~55, fastest ~85
@@ -280,21 +178,31 @@ func validateTeamRatings(team *BarttorkvikTeam, logger *zap.Logger) bool {
 		return false
 	}
 
-	// Tempo validation (allow default if not parsed)
-	if team.AdjTempo != 70.0 && (team.AdjTempo < tempoMin || team.AdjTempo > tempoMax) {
-		logger.Debug("Invalid tempo, using default", zap.String("team", team.Team), zap.Float64("tempo", team.AdjTempo))
-		team.AdjTempo = 70.0 // Reset to safe default
+	// Tempo validation. Strict: reject (a defaulted 70.0 would feed the totals model
+	// a fake pace). Legacy: allow default if not parsed.
+	if team.AdjTempo < tempoMin || team.AdjTempo > tempoMax {
+		if strict {
+			logger.Debug("Invalid tempo", zap.String("team", team.Team), zap.Float64("tempo", team.AdjTempo))
+			return false
+		}
+		if team.AdjTempo != 70.0 {
+			logger.Debug("Invalid tempo, using default", zap.String("team", team.Team), zap.Float64("tempo", team.AdjTempo))
+			team.AdjTempo = 70.0 // Reset to safe default
+		}
 	}
 
-	// Barthag should be 0-1 probability
-	if team.Barthag < 0 || team.Barthag > 1 {
+	// Barthag should be 0-1 probability (exactly 0 means it never parsed)
+	if team.Barthag <= 0 || team.Barthag > 1 {
 		logger.Debug("Invalid Barthag", zap.String("team", team.Team), zap.Float64("barthag", team.Barthag))
-		// Not fatal, can still use team
+		if strict {
+			return false
+		}
+		// Legacy: not fatal, can still use team
 	}
 
 	// Four factors - soft validation (warn but don't skip)
-	if team.EFG < 30 || team.EFG > 70 {
-		logger.Debug("Unusual EFG%", zap.String("team", team.Team), zap.Float64("efg", team.EFG))
+	if team.EFG != nil && (*team.EFG < 30 || *team.EFG > 70) {
+		logger.Debug("Unusual EFG%", zap.String("team", team.Team), zap.Float64("efg", *team.EFG))
 	}
 
 	return true
@@ -302,6 +210,15 @@ func validateTeamRatings(team *BarttorkvikTeam, logger *zap.Logger) bool {
 
 // doRequestWithRetry executes an HTTP request with retries on transient errors.
 // Retries on network errors, 429 Too Many Requests, and 5xx status codes.
+// statusError is a non-200 response that retries did not clear
+type statusError struct {
+	Code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.Code)
+}
+
 func doRequestWithRetry(ctx context.Context, req *http.Request, maxAttempts int) (*http.Response, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	var lastErr error
@@ -339,7 +256,7 @@ func doRequestWithRetry(ctx context.Context, req *http.Request, maxAttempts int)
 				return nil, fmt.Errorf("fetching ratings: %w", err)
 			}
 			if resp != nil {
-				return resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+				return resp, &statusError{Code: resp.StatusCode}
 			}
 			return nil, fmt.Errorf("request failed with no response: %v", lastErr)
 		}
@@ -370,70 +287,8 @@ func doRequestWithRetry(ctx context.Context, req *http.Request, maxAttempts int)
 	return nil, fmt.Errorf("all %d attempts failed: %v", maxAttempts, lastErr)
 }
 
-// Helper functions to safely convert interface{} to types
-func toInt(v interface{}) int {
-	switch val := v.(type) {
-	case float64:
-		return int(val)
-	case int:
-		return val
-	case string:
-		i, _ := strconv.Atoi(val)
-		return i
-	}
-	return 0
-}
-
-func toFloat(v interface{}) float64 {
-	switch val := v.(type) {
-	case float64:
-		return val
-	case int:
-		return float64(val)
-	case string:
-		f, _ := strconv.ParseFloat(val, 64)
-		return f
-	}
-	return 0.0
-}
-
-func toString(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		return val
-	case float64:
-		return strconv.FormatFloat(val, 'f', -1, 64)
-	case int:
-		return strconv.Itoa(val)
-	}
-	return ""
-}
-
-func getFloat(m map[string]interface{}, key string, defaultVal float64) float64 {
-	if v, ok := m[key]; ok {
-		return toFloat(v)
-	}
-	return defaultVal
-}
-
-func getInt(m map[string]interface{}, key string, defaultVal int) int {
-	if v, ok := m[key]; ok {
-		return toInt(v)
-	}
-	return defaultVal
-}
-
-func parseRecord(record string) (wins, losses int) {
-	parts := strings.Split(record, "-")
-	if len(parts) == 2 {
-		wins, _ = strconv.Atoi(parts[0])
-		losses, _ = strconv.Atoi(parts[1])
-	}
-	return
-}
-
-// StoreRatings stores ratings in the database
-func (r *RatingsSync) StoreRatings(ctx context.Context, teams []BarttorkvikTeam) error {
+// StoreRatings stores ratings in the database and returns the number of rows written
+func (r *RatingsSync) StoreRatings(ctx context.Context, teams []BarttorkvikTeam) (int, error) {
 	// FIX: Use UTC for consistent date storage across all services
 	// This ensures ratings align with games stored in UTC by the Rust service
 	today := time.Now().UTC().Format("2006-01-02")
@@ -443,7 +298,7 @@ func (r *RatingsSync) StoreRatings(ctx context.Context, teams []BarttorkvikTeam)
 	// Start transaction
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("starting transaction: %w", err)
+		return 0, fmt.Errorf("starting transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
@@ -551,12 +406,20 @@ func (r *RatingsSync) StoreRatings(ctx context.Context, teams []BarttorkvikTeam)
 		stored++
 	}
 
+	// Checked before commit so a truncated day never replaces a full one
+	if err := r.verifySnapshotSize(ctx, tx, today, stored); err != nil {
+		return 0, err
+	}
+
+	// Outbox: change events commit atomically with the ratings they describe
+	r.emitRatingChanges(ctx, tx, today)
+
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("committing transaction: %w", err)
+		return 0, fmt.Errorf("committing transaction: %w", err)
 	}
 
 	r.logger.Info("Stored ratings successfully", zap.Int("stored", stored), zap.Int("total", len(teams)))
-	return nil
+	return stored, nil
 }
 
 // ensureTeam makes sure the team exists in the database
@@ -696,44 +559,128 @@ func normalizeTeamName(name string) string {
 	return strings.TrimSpace(name)
 }
 
+// SyncStats summarizes a single sync run
+type SyncStats struct {
+	Fetched       int // Teams parsed and validated from Barttorvik
+	ParseSkipped  int // Rows dropped as incomplete/invalid before storing
+	MissingFields int // Secondary metric values that were absent (stored as NULL in strict mode)
+	Stored        int // Rating rows written
+}
+
 // Sync performs a full sync
-func (r *RatingsSync) Sync(ctx context.Context) error {
+func (r *RatingsSync) Sync(ctx context.Context) (SyncStats, error) {
+	var stats SyncStats
 	start := time.Now()
 	r.logger.Info("Starting ratings sync")
+	r.reportAliasConflicts(ctx)
 
-	teams, err := r.FetchRatings(ctx)
-	if err != nil {
-		r.logger.Error("Fetch ratings failed", zap.Error(err))
-		// TODO: Integrate with alerting system (e.g., email, Slack, PagerDuty)
-		fmt.Println("ALERT: Fetch ratings failed: " + err.Error())
-		return fmt.Errorf("fetching ratings: %w", err)
-	}
+	var teams []BarttorkvikTeam
+	for attempt := 1; ; attempt++ {
+		var report ParseReport
+		var err error
+		teams, report, err = r.FetchRatings(ctx)
+		if err != nil {
+			r.logger.Error("Fetch ratings failed", zap.Error(err))
+			// TODO: Integrate with alerting system (e.g., email, Slack, PagerDuty)
+			fmt.Println("ALERT: Fetch ratings failed: " + err.Error())
+			if isProviderFailure(err, r.backfilling) {
+				r.recordOutage(ctx, providerBarttorvik, outageFetchFailed, err)
+			}
+			return stats, &SyncError{Code: ExitFetchFailed, Err: fmt.Errorf("fetching ratings: %w", err)}
+		}
+		stats.Fetched = len(teams)
+		stats.ParseSkipped = report.Skipped
+		stats.MissingFields = report.MissingTotal()
+
+		if stats.Fetched == 0 && r.config.FailOnZeroRows {
+			fmt.Println("ALERT: Barttorvik returned zero usable teams")
+			return stats, &SyncError{Code: ExitNoRows, Err: fmt.Errorf("zero usable teams fetched (%d rows skipped)", report.Skipped)}
+		}
+
+		stats.Stored, err = r.StoreRatings(ctx, teams)
+		if err == nil {
+			break
+		}
+
+		// A partial Barttorvik snapshot was rolled back; it usually completes within minutes
+		var syncErr *SyncError
+		if errors.As(err, &syncErr) && syncErr.Code == ExitPartialSnapshot {
+			if attempt <= r.config.PartialRetries {
+				r.logger.Warn("Partial snapshot rejected, retrying",
+					zap.Error(err),
+					zap.Int("attempt", attempt),
+					zap.Duration("delay", r.config.PartialRetryDelay))
+				if err := r.waitForRetry(ctx); err != nil {
+					return stats, err
+				}
+				continue
+			}
+			r.logger.Error("Partial snapshot rejected", zap.Error(err))
+			fmt.Println("ALERT: " + err.Error())
+			r.recordOutage(ctx, providerBarttorvik, outagePartialSnapshot, err)
+			return stats, err
+		}
 
-	if err := r.StoreRatings(ctx, teams); err != nil {
 		r.logger.Error("Store ratings failed", zap.Error(err))
 		// TODO: Integrate with alerting system (e.g., email, Slack, PagerDuty)
 		fmt.Println("ALERT: Store ratings failed: " + err.Error())
-		return fmt.Errorf("storing ratings: %w", err)
+		return stats, &SyncError{Code: ExitStoreFailed, Err: fmt.Errorf("storing ratings: %w", err)}
+	}
+
+	// A full snapshot committed: Barttorvik is healthy again
+	r.resolveOutage(ctx, providerBarttorvik)
+
+	if err := r.finishSync(ctx, stats, r.derivedSteps(), time.Now()); err != nil {
+		return stats, err
 	}
 
 	r.logger.Info("Ratings sync completed",
 		zap.Duration("duration", time.Since(start)),
-		zap.Int("teams", len(teams)))
+		zap.Int("teams", len(teams)),
+		zap.Int("missing_fields", stats.MissingFields))
 
-	return nil
+	return stats, nil
 }
 
-// readSecretFile reads a secret from Docker secret file - REQUIRED, NO fallbacks
-func readSecretFile(filePath string, secretName string) string {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Fatalf("CRITICAL: Secret file not found: %s (%s). Container must have secrets mounted.", filePath, secretName)
+// derivedStep is a best-effort table refresh run after each committed snapshot
+type derivedStep struct {
+	name   string
+	run    func(ctx context.Context, asOf time.Time) error
+	tables []string // optional tables the step writes; skipped if any is unavailable
+}
+
+// derivedSteps lists the derived-table refreshes in dependency order
+func (r *RatingsSync) derivedSteps() []derivedStep {
+	return []derivedStep{
+		{"quadrant records", r.ComputeQuadrantRecords, []string{"team_quadrant_records"}},
+		{"conference metrics", r.ComputeConferenceMetrics, []string{"conference_metrics"}},
+		{"rating percentiles", r.ComputeRatingPercentiles, []string{"team_rating_percentiles"}},
+		{"recent form", r.ComputeRecentForm, []string{"team_recent_form"}},
+		{"season projections", r.ProjectStandingsIfStale, []string{"team_season_projections"}},
 	}
-	password := strings.TrimSpace(string(data))
-	if password == "" {
-		log.Fatalf("CRITICAL: Secret file %s is empty (%s).", filePath, secretName)
+}
+
+// finishSync refreshes derived tables for a committed snapshot, then applies the
+// skip threshold. Steps are best-effort and run even when the threshold fails:
+// the day's ratings are already committed, so skipping them would leave derived
+// tables stale against the new ratings.
+func (r *RatingsSync) finishSync(ctx context.Context, stats SyncStats, steps []derivedStep, asOf time.Time) error {
+	for _, step := range steps {
+		if !r.schemaReady(step.tables...) {
+			r.logger.Warn("Derived table refresh skipped: schema unavailable", zap.String("step", step.name), zap.Strings("tables", step.tables))
+			continue
+		}
+		if err := step.run(ctx, asOf); err != nil {
+			r.logger.Warn("Derived table refresh failed", zap.String("step", step.name), zap.Error(err))
+		}
 	}
-	return password
+
+	if err := r.config.checkSkipThreshold(stats); err != nil {
+		r.logger.Error("Skipped-team threshold exceeded", zap.Error(err))
+		fmt.Println("ALERT: " + err.Error())
+		return err
+	}
+	return nil
 }
 
 // getCurrentSeason calculates the current NCAA basketball season
@@ -751,6 +698,18 @@ func getCurrentSeason() int {
 }
 
 func main() {
+	// Subcommands: `ratings-sync config validate|print`, `ratings-sync aliases check|resolve|remove`,
+	// `ratings-sync standings`
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "aliases" {
+		os.Exit(runAliasesCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "standings" {
+		os.Exit(runStandingsCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// NO .env file loading - all secrets MUST come from Docker secret files
 
 	// Initialize logger
@@ -760,69 +719,30 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// Parse config
-	// - Docker Compose: DATABASE_URL is not set; we build it from /run/secrets/db_password
-	// - Azure Container Apps: DATABASE_URL is set via env vars; no /run/secrets mount exists
-
-	// Sport-parameterized database configuration (enables multi-sport deployment)
-	sport := os.Getenv("SPORT")
-	if sport == "" {
-		sport = "ncaam"
-	}
-	dbUser := os.Getenv("DB_USER")
-	if dbUser == "" {
-		dbUser = sport
-	}
-	dbName := os.Getenv("DB_NAME")
-	if dbName == "" {
-		dbName = sport
-	}
-	dbHost := os.Getenv("DB_HOST")
-	if dbHost == "" {
-		dbHost = "postgres"
-	}
-	dbPort := os.Getenv("DB_PORT")
-	if dbPort == "" {
-		dbPort = "5432"
-	}
-
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		// Read database password from Docker secret file - REQUIRED in Docker Compose
-		dbPassword := readSecretFile("/run/secrets/db_password", "db_password")
-		databaseURL = fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", dbUser, dbPassword, dbHost, dbPort, dbName)
-	}
-
-	config := Config{
-		DatabaseURL: databaseURL,
-		Season:      getCurrentSeason(),
-		// MANUAL-ONLY: Default to run once and exit (no cron automation)
-		// User triggers via run_today.py when they want fresh picks
-		// Case-insensitive check to match Rust service behavior
-		RunOnce: strings.ToLower(os.Getenv("RUN_ONCE")) != "false", // Default true
-		BackfillFrom: 0,
-		BackfillTo:   0,
-		// Team matching guardrails (aligned with Rust odds-ingestion service)
-		StrictTeamMatching: strings.ToLower(os.Getenv("STRICT_TEAM_MATCHING")) != "false", // Default true
-		AllowTeamCreation:  strings.ToLower(os.Getenv("ALLOW_TEAM_CREATION")) == "true",  // Default false
+	// Parse config for the deployment profile (see config.go) and fail fast on
+	// misconfiguration rather than mid-sync
+	profile, err := resolveProfile("")
+	if err != nil {
+		logger.Fatal("CRITICAL: invalid CONFIG_PROFILE", zap.Error(err))
 	}
-
-	if config.DatabaseURL == "" {
-		logger.Fatal("CRITICAL: DATABASE_URL not configured. Provide DATABASE_URL env var (Azure) or mount /run/secrets/db_password (Docker Compose).")
+	config, err := loadConfig(profile)
+	if err != nil {
+		logger.Fatal("CRITICAL: "+err.Error(), zap.String("profile", profile))
 	}
-
-	// Override season if provided
-	if s := os.Getenv("SEASON"); s != "" {
-		if parsed, err := strconv.Atoi(s); err == nil {
-			config.Season = parsed
+	if errs := config.Validate(); len(errs) > 0 {
+		for _, e := range errs {
+			logger.Error("Invalid configuration", zap.String("profile", profile), zap.Error(e))
 		}
+		logger.Fatal("CRITICAL: configuration invalid; run `ratings-sync config validate` for details")
 	}
 
 	logger.Info("Starting Ratings Sync Service",
+		zap.String("profile", config.Profile),
 		zap.Int("season", config.Season),
 		zap.Bool("run_once", config.RunOnce),
 		zap.Bool("strict_team_matching", config.StrictTeamMatching),
 		zap.Bool("allow_team_creation", config.AllowTeamCreation),
+		zap.Bool("strict_parsing", config.StrictParsing),
 	)
 
 	// Connect to database
@@ -836,39 +756,20 @@ func main() {
 	// Create sync service
 	sync := NewRatingsSync(db, logger, config)
 
-	// Optional backfill range: BACKFILL_SEASONS="2024-2026" or "2024"
-	if bf := os.Getenv("BACKFILL_SEASONS"); bf != "" {
-		parts := strings.Split(bf, "-")
-		if len(parts) == 2 {
-			if from, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
-				config.BackfillFrom = from
-			}
-			if to, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-				config.BackfillTo = to
-			}
-		} else if len(parts) == 1 {
-			if yr, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
-				config.BackfillFrom = yr
-				config.BackfillTo = yr
-			}
-		}
+	// Fail fast on schema drift instead of erroring mid-sync
+	if err := sync.CheckSchema(ctx); err != nil {
+		logger.Fatal("CRITICAL: "+err.Error(), zap.Error(err))
+	}
 
-		if config.BackfillFrom != 0 && config.BackfillTo != 0 {
-			start := config.BackfillFrom
-			end := config.BackfillTo
-			if start > end {
-				start, end = end, start
-			}
-			for season := start; season <= end; season++ {
-				logger.Info("Backfill season", zap.Int("season", season))
-				sync.config.Season = season
-				if err := sync.Sync(ctx); err != nil {
-					logger.Error("Backfill sync failed", zap.Int("season", season), zap.Error(err))
-				}
-			}
-			logger.Info("Backfill completed", zap.Int("from", start), zap.Int("to", end))
-			return
+	// Optional backfill range: BACKFILL_SEASONS="2024-2026" or "2024"
+	if config.BackfillFrom != 0 && config.BackfillTo != 0 {
+		resume := strings.ToLower(os.Getenv("BACKFILL_RESUME")) == "true"
+		if err := sync.Backfill(ctx, config.BackfillFrom, config.BackfillTo, resume); err != nil {
+			logger.Error("Backfill failed", zap.Error(err), zap.Int("exit_code", exitCodeFor(err)))
+			logger.Sync()
+			os.Exit(exitCodeFor(err))
 		}
+		return
 	}
 
 	// MANUAL-ONLY MODE: Always run once and exit (no cron automation)
@@ -877,8 +778,10 @@ func main() {
 		logger.Fatal("RUN_ONCE=false is not supported. This service is manual-only. Use RUN_ONCE=true for manual runs.")
 	}
 
-	if err := sync.Sync(ctx); err != nil {
-		logger.Fatal("Sync failed", zap.Error(err))
+	if _, err := sync.Sync(ctx); err != nil {
+		logger.Error("Sync failed", zap.Error(err), zap.Int("exit_code", exitCodeFor(err)))
+		logger.Sync()
+		os.Exit(exitCodeFor(err))
 	}
 	logger.Info("Manual sync completed successfully")
 }
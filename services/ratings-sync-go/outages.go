@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// Provider names and outage kinds recorded in provider_outages
+const (
+	providerBarttorvik = "barttorvik"
+
+	outageFetchFailed     = "fetch_failed"     // Request failed after all retries, or response undecodable
+	outagePartialSnapshot = "partial_snapshot" // Payload kept coming back truncated
+)
+
+// isProviderFailure reports whether a fetch error says anything about the
+// provider's health. A backfill 404 is a season Barttorvik never published.
+func isProviderFailure(err error, backfilling bool) bool {
+	var status *statusError
+	return !(backfilling && errors.As(err, &status) && status.Code == http.StatusNotFound)
+}
+
+// recordOutage counts a failed run against the provider's open outage row,
+// creating it on the first failure. The outage is confirmed (and starts counting
+// as a degraded window) once OutageThreshold consecutive runs have failed.
+// Outage bookkeeping is best-effort: it must never mask the sync error being reported.
+func (r *RatingsSync) recordOutage(ctx context.Context, provider, kind string, cause error) {
+	if !r.schemaReady("provider_outages") {
+		return
+	}
+	var failures int
+	var confirmed bool
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO provider_outages (provider, kind, last_error, confirmed_at)
+		VALUES ($1, $2, $3, CASE WHEN $4 <= 1 THEN NOW() END)
+		ON CONFLICT (provider) WHERE ended_at IS NULL DO UPDATE SET
+			failure_count = provider_outages.failure_count + 1,
+			last_failure_at = NOW(),
+			kind = EXCLUDED.kind,
+			last_error = EXCLUDED.last_error,
+			confirmed_at = COALESCE(provider_outages.confirmed_at,
+				CASE WHEN provider_outages.failure_count + 1 >= $4 THEN NOW() END)
+		RETURNING failure_count, confirmed_at IS NOT NULL
+	`, provider, kind, cause.Error(), r.config.OutageThreshold).Scan(&failures, &confirmed)
+	if err != nil {
+		r.logger.Warn("Failed to record provider outage", zap.String("provider", provider), zap.Error(err))
+		return
+	}
+	if !confirmed {
+		r.logger.Warn("Provider failure recorded, below outage threshold",
+			zap.String("provider", provider),
+			zap.String("kind", kind),
+			zap.Int("consecutive_failures", failures),
+			zap.Int("threshold", r.config.OutageThreshold))
+		return
+	}
+	r.logger.Warn("Provider outage recorded",
+		zap.String("provider", provider),
+		zap.String("kind", kind),
+		zap.Int("consecutive_failures", failures))
+}
+
+// resolveOutage closes any open outage for provider after a successful sync.
+// Failures that never reached the threshold were transient and are dropped.
+func (r *RatingsSync) resolveOutage(ctx context.Context, provider string) {
+	if !r.schemaReady("provider_outages") {
+		return
+	}
+	var confirmed bool
+	err := r.db.QueryRow(ctx, `
+		WITH dropped AS (
+			DELETE FROM provider_outages
+			WHERE provider = $1 AND ended_at IS NULL AND confirmed_at IS NULL
+		), closed AS (
+			UPDATE provider_outages
+			SET ended_at = NOW()
+			WHERE provider = $1 AND ended_at IS NULL AND confirmed_at IS NOT NULL
+			RETURNING 1
+		)
+		SELECT EXISTS (SELECT 1 FROM closed)
+	`, provider).Scan(&confirmed)
+	if err != nil {
+		r.logger.Warn("Failed to resolve provider outage", zap.String("provider", provider), zap.Error(err))
+		return
+	}
+	if confirmed {
+		r.logger.Info("Provider outage resolved", zap.String("provider", provider))
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsProviderFailure(t *testing.T) {
+	notFound := fmt.Errorf("fetching ratings: %w", &statusError{Code: 404})
+	tests := []struct {
+		name        string
+		err         error
+		backfilling bool
+		want        bool
+	}{
+		{"backfill 404", notFound, true, false},
+		{"current season 404", notFound, false, true},
+		{"backfill 503", &statusError{Code: 503}, true, true},
+		{"backfill network error", errors.New("connection reset"), true, true},
+		{"timeout", context.DeadlineExceeded, false, true},
+	}
+	for _, tt := range tests {
+		if got := isProviderFailure(tt.err, tt.backfilling); got != tt.want {
+			t.Errorf("%s: isProviderFailure = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
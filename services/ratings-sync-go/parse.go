@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// maxIntField bounds integer fields (ranks, wins) before conversion
+const maxIntField = 1e6
+
+// criticalFields are the Barttorvik row indices that must parse as finite numbers.
+// Everything the efficiency model needs: AdjOE, AdjDE, Barthag, AdjTempo.
+var criticalFields = []struct {
+	name  string
+	index int
+}{
+	{"adjoe", 4},
+	{"adjde", 6},
+	{"barthag", 8},
+	{"adj_t", 44},
+}
+
+// missingCriticalField returns the first critical field that fails to parse
+func missingCriticalField(raw []interface{}) (string, bool) {
+	for _, f := range criticalFields {
+		if _, ok := toFloatOK(raw[f.index]); !ok {
+			return f.name, false
+		}
+	}
+	return "", true
+}
+
+// ParseReport summarizes one parse pass over a Barttorvik payload
+type ParseReport struct {
+	Skipped int            // Rows rejected as incomplete/invalid
+	Missing map[string]int // Secondary metrics absent or unparseable, by field
+}
+
+// MissingTotal returns the number of missing secondary metric values
+func (p ParseReport) MissingTotal() int {
+	total := 0
+	for _, n := range p.Missing {
+		total += n
+	}
+	return total
+}
+
+// parseTeams converts a decoded Barttorvik array-of-arrays payload into teams.
+// Returns a report of skipped rows and missing fields, or an error if the
+// payload format itself has changed.
+func parseTeams(rawTeams [][]interface{}, strict bool, logger *zap.Logger) ([]BarttorkvikTeam, ParseReport, error) {
+	report := ParseReport{Missing: make(map[string]int)}
+
+	// Format validation: check first row structure
+	if len(rawTeams) > 0 {
+		first := rawTeams[0]
+		sample := ""
+		if len(first) > 1 {
+			sample = toString(first[1])
+		}
+		logger.Info("Barttorvik format check",
+			zap.Int("field_count", len(first)),
+			zap.String("sample_team", sample),
+		)
+		// Expected: 45 fields for 2025-26. Log warning if format changed.
+		if len(first) < 25 {
+			logger.Error("Barttorvik format changed - too few fields",
+				zap.Int("expected_min", 25),
+				zap.Int("actual", len(first)),
+			)
+			return nil, report, fmt.Errorf("barttorvik format changed: expected >=25 fields, got %d", len(first))
+		}
+		if len(first) < 40 || len(first) > 50 {
+			logger.Warn("Barttorvik format may have changed - unusual field count",
+				zap.Int("expected_range", 45),
+				zap.Int("actual", len(first)),
+			)
+		}
+	}
+
+	var teams []BarttorkvikTeam
+	for _, raw := range rawTeams {
+		// 2025-26 season: Barttorvik returns 45 fields (indices 0-44)
+		// AdjTempo is at index 44 (last element)
+		if len(raw) < 45 {
+			report.Skipped++
+			continue // Skip incomplete records - need all metrics
+		}
+
+		// A non-string team name means the row is shifted or corrupt
+		if name, ok := raw[1].(string); !ok || strings.TrimSpace(name) == "" {
+			report.Skipped++
+			continue
+		}
+
+		// Critical prediction inputs must be real numbers; a parse failure here
+		// would otherwise become a silent 0 (or the 70.0 tempo default)
+		if field, ok := missingCriticalField(raw); !ok {
+			logger.Debug("Skipping row with unparseable critical metric",
+				zap.String("team", toString(raw[1])),
+				zap.String("field", field),
+			)
+			report.Skipped++
+			continue
+		}
+
+		// Direct index mapping based on actual Barttorvik 2025 JSON format:
+		// [0]=rank, [1]=team, [2]=conf, [3]=record, [4]=adjoe, [5]=adjoe_rank,
+		// [6]=adjde, [7]=adjde_rank, [8]=barthag, [9]=barthag_rank,
+		// [10]=wins, [11]=losses, [12]=conf_wins, [13]=conf_losses, [14]=conf_record,
+		// [15]=efg_o, [16]=efg_d, [17]=tor, [18]=tord, [19]=orb_o, [20]=drb_d,
+		// [21]=ftr_o, [22]=ftr_d, [23]=2p_o, [24]=2p_d, [25]=3p_o, [26]=3p_d,
+		// [27]=3pr_o, [28]=3pr_d, [29-43]=various advanced stats,
+		// [44]=adj_tempo (LAST FIELD)
+		dataMap := make(map[string]interface{})
+		dataMap["rank"] = raw[0]
+		dataMap["team"] = raw[1]
+		dataMap["conf"] = raw[2]
+		dataMap["record"] = raw[3]
+		dataMap["adjoe"] = raw[4]
+		dataMap["adjde"] = raw[6]
+		dataMap["barthag"] = raw[8]
+		dataMap["wins"] = raw[10]
+		dataMap["losses"] = raw[11]
+		dataMap["efg"] = raw[15]
+		dataMap["efgd"] = raw[16]
+		dataMap["tor"] = raw[17]
+		dataMap["tord"] = raw[18]
+		dataMap["orb"] = raw[19]
+		dataMap["drb"] = raw[20]
+		dataMap["ftr"] = raw[21]
+		dataMap["ftrd"] = raw[22]
+		dataMap["2p"] = raw[23]
+		dataMap["2pd"] = raw[24]
+		dataMap["3p"] = raw[25]
+		dataMap["3pd"] = raw[26]
+		dataMap["3pr"] = raw[27]
+		dataMap["3prd"] = raw[28]
+		dataMap["adj_t"] = raw[44] // TEMPO IS THE LAST FIELD
+		// WAB doesn't have a consistent position, so it is never mapped: it is
+		// stored as NULL and counted as missing rather than faked as 0
+		report.Missing["wab"]++
+
+		// Parse wins/losses from the dedicated fields (more reliable than record string)
+		wins := getInt(dataMap, "wins", 0)
+		losses := getInt(dataMap, "losses", 0)
+
+		// Extract with defaults and validation
+		adjTempo := getFloat(dataMap, "adj_t", 70.0)
+		opt := func(key string) *float64 {
+			return getOptFloat(dataMap, key, strict, report.Missing)
+		}
+
+		team := BarttorkvikTeam{
+			// Core identifiers
+			Rank: getInt(dataMap, "rank", 0),
+			Team: toString(dataMap["team"]),
+			Conf: toString(dataMap["conf"]),
+
+			// Efficiency ratings (primary prediction inputs)
+			AdjOE:    getFloat(dataMap, "adjoe", 0.0),
+			AdjDE:    getFloat(dataMap, "adjde", 0.0),
+			AdjTempo: adjTempo,
+
+			// Record
+			Wins:   wins,
+			Losses: losses,
+			G:      wins + losses,
+
+			// Quality metrics
+			Barthag: getFloat(dataMap, "barthag", 0.0),
+			WAB:     nil, // see above
+
+			// Four Factors - Shooting
+			EFG:  opt("efg"),
+			EFGD: opt("efgd"),
+
+			// Four Factors - Turnovers
+			TOR:  opt("tor"),
+			TORD: opt("tord"),
+
+			// Four Factors - Rebounding
+			ORB: opt("orb"),
+			DRB: opt("drb"),
+
+			// Four Factors - Free Throws
+			FTR:  opt("ftr"),
+			FTRD: opt("ftrd"),
+
+			// Shooting breakdown (using dataMap which has correct indices)
+			TwoP:     opt("2p"),
+			TwoPD:    opt("2pd"),
+			ThreeP:   opt("3p"),
+			ThreePD:  opt("3pd"),
+			ThreePR:  opt("3pr"),
+			ThreePRD: opt("3prd"),
+		}
+
+		// Validate parsed values are in reasonable ranges
+		if !validateTeamRatings(&team, strict, logger) {
+			logger.Warn("Skipping team with invalid ratings",
+				zap.String("team", team.Team),
+				zap.Float64("adj_o", team.AdjOE),
+				zap.Float64("adj_d", team.AdjDE),
+			)
+			report.Skipped++
+			continue
+		}
+
+		teams = append(teams, team)
+	}
+
+	if report.Skipped > 0 {
+		logger.Warn("Skipped teams with incomplete/invalid data", zap.Int("skipped", report.Skipped))
+	}
+	if total := report.MissingTotal(); total > 0 {
+		logger.Warn("Missing Barttorvik fields",
+			zap.Int("missing_total", total),
+			zap.Any("missing_by_field", report.Missing),
+			zap.Bool("stored_as_null", strict),
+		)
+	}
+
+	return teams, report, nil
+}
+
+// Helper functions to safely convert interface{} to types
+func toInt(v interface{}) int {
+	if i, ok := v.(int); ok {
+		return i
+	}
+	f, ok := toFloatOK(v)
+	// Out-of-range values would overflow int conversion
+	if !ok || f > maxIntField || f < -maxIntField {
+		return 0
+	}
+	return int(f)
+}
+
+func toFloat(v interface{}) float64 {
+	f, _ := toFloatOK(v)
+	return f
+}
+
+// toFloatOK converts a JSON value to a finite float64.
+// Returns false for nil, non-numeric strings, NaN/Inf, and unsupported types.
+func toFloatOK(v interface{}) (float64, bool) {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case int:
+		f = float64(val)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return 0, false
+		}
+		f = parsed
+	default:
+		return 0, false
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(val)
+	}
+	return ""
+}
+
+func getFloat(m map[string]interface{}, key string, defaultVal float64) float64 {
+	if v, ok := m[key]; ok {
+		return toFloat(v)
+	}
+	return defaultVal
+}
+
+// getOptFloat returns a secondary metric, recording it in missing when absent or
+// unparseable. Strict mode returns nil (stored as NULL); legacy mode returns 0.
+func getOptFloat(m map[string]interface{}, key string, strict bool, missing map[string]int) *float64 {
+	if f, ok := toFloatOK(m[key]); ok {
+		return &f
+	}
+	missing[key]++
+	if strict {
+		return nil
+	}
+	zero := 0.0
+	return &zero
+}
+
+func getInt(m map[string]interface{}, key string, defaultVal int) int {
+	if v, ok := m[key]; ok {
+		return toInt(v)
+	}
+	return defaultVal
+}
+
+// parseRecord parses a "W-L" record string. Malformed or negative records yield 0-0.
+func parseRecord(record string) (wins, losses int) {
+	w, l, found := strings.Cut(strings.TrimSpace(record), "-")
+	if !found {
+		return 0, 0
+	}
+	wins, errW := strconv.Atoi(strings.TrimSpace(w))
+	losses, errL := strconv.Atoi(strings.TrimSpace(l))
+	if errW != nil || errL != nil || wins < 0 || losses < 0 {
+		return 0, 0
+	}
+	return wins, losses
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// sampleRow returns a realistic 45-field Barttorvik row (2025-26 format)
+func sampleRow() []interface{} {
+	row := make([]interface{}, 45)
+	for i := range row {
+		row[i] = 0.0
+	}
+	row[0] = 1.0
+	row[1] = "Duke"
+	row[2] = "ACC"
+	row[3] = "20-2"
+	row[4] = 122.4
+	row[6] = 91.8
+	row[8] = 0.9712
+	row[10] = 20.0
+	row[11] = 2.0
+	row[15] = 56.1
+	row[16] = 44.9
+	row[17] = 15.2
+	row[18] = 19.8
+	row[19] = 33.4
+	row[20] = 27.1
+	row[21] = 35.5
+	row[22] = 28.0
+	row[23] = 55.0
+	row[24] = 44.2
+	row[25] = 38.1
+	row[26] = 30.5
+	row[27] = 40.2
+	row[28] = 36.7
+	row[44] = 66.9
+	return row
+}
+
+func TestParseTeamsMapsIndices(t *testing.T) {
+	teams, report, err := parseTeams([][]interface{}{sampleRow()}, true, zap.NewNop())
+	if err != nil {
+		t.Fatalf("parseTeams: %v", err)
+	}
+	if report.Skipped != 0 || len(teams) != 1 {
+		t.Fatalf("got %d teams, %d skipped; want 1, 0", len(teams), report.Skipped)
+	}
+	got := teams[0]
+	if got.Team != "Duke" || got.Conf != "ACC" || got.Rank != 1 {
+		t.Errorf("identifiers = %q %q %d", got.Team, got.Conf, got.Rank)
+	}
+	if got.AdjOE != 122.4 || got.AdjDE != 91.8 || got.AdjTempo != 66.9 {
+		t.Errorf("efficiency = %v %v %v", got.AdjOE, got.AdjDE, got.AdjTempo)
+	}
+	if got.Wins != 20 || got.Losses != 2 || got.G != 22 {
+		t.Errorf("record = %d-%d (%d games)", got.Wins, got.Losses, got.G)
+	}
+	if got.ThreePR == nil || *got.ThreePR != 40.2 || got.ThreePRD == nil || *got.ThreePRD != 36.7 {
+		t.Errorf("3PR = %v %v", got.ThreePR, got.ThreePRD)
+	}
+}
+
+func TestParseTeamsStrictMissingFields(t *testing.T) {
+	row := sampleRow()
+	row[15] = nil // efg_o
+	row[19] = ""  // orb
+
+	for _, strict := range []bool{true, false} {
+		teams, report, err := parseTeams([][]interface{}{row}, strict, zap.NewNop())
+		if err != nil || len(teams) != 1 {
+			t.Fatalf("strict=%v: got %d teams, err %v", strict, len(teams), err)
+		}
+		if report.Missing["efg"] != 1 || report.Missing["orb"] != 1 || report.MissingTotal() != 3 {
+			t.Errorf("strict=%v: missing = %v", strict, report.Missing)
+		}
+		team := teams[0]
+		if strict && (team.EFG != nil || team.ORB != nil) {
+			t.Errorf("strict: want nil for missing fields, got efg=%v orb=%v", team.EFG, team.ORB)
+		}
+		if team.WAB != nil {
+			t.Errorf("strict=%v: want nil WAB, got %v", strict, *team.WAB)
+		}
+		if !strict && (team.EFG == nil || *team.EFG != 0) {
+			t.Errorf("legacy: want zero-filled efg, got %v", team.EFG)
+		}
+	}
+}
+
+func TestParseTeamsStrictRejectsOutOfRange(t *testing.T) {
+	zeroTempo := sampleRow()
+	zeroTempo[44] = 0.0
+
+	zeroBarthag := sampleRow()
+	zeroBarthag[8] = 0.0
+
+	rows := [][]interface{}{zeroTempo, zeroBarthag}
+
+	teams, report, _ := parseTeams(rows, true, zap.NewNop())
+	if len(teams) != 0 || report.Skipped != 2 {
+		t.Errorf("strict: got %d teams, %d skipped; want 0, 2", len(teams), report.Skipped)
+	}
+
+	teams, report, _ = parseTeams(rows, false, zap.NewNop())
+	if len(teams) != 2 || report.Skipped != 0 {
+		t.Fatalf("legacy: got %d teams, %d skipped; want 2, 0", len(teams), report.Skipped)
+	}
+	if teams[0].AdjTempo != 70.0 {
+		t.Errorf("legacy: tempo = %v, want 70.0 default", teams[0].AdjTempo)
+	}
+}
+
+func TestParseTeamsSkipsMalformedRows(t *testing.T) {
+	numericName := sampleRow()
+	numericName[1] = 12.0
+
+	nanTempo := sampleRow()
+	nanTempo[44] = "NaN"
+
+	missingAdjDE := sampleRow()
+	missingAdjDE[6] = nil
+
+	rows := [][]interface{}{sampleRow(), numericName, nanTempo, missingAdjDE, sampleRow()[:30]}
+	teams, report, err := parseTeams(rows, true, zap.NewNop())
+	if err != nil {
+		t.Fatalf("parseTeams: %v", err)
+	}
+	if len(teams) != 1 || report.Skipped != 4 {
+		t.Fatalf("got %d teams, %d skipped; want 1, 4", len(teams), report.Skipped)
+	}
+}
+
+func TestParseTeamsShortFirstRow(t *testing.T) {
+	// Previously panicked indexing first[1] for the format log line
+	if _, _, err := parseTeams([][]interface{}{{1.0}}, true, zap.NewNop()); err == nil {
+		t.Fatal("expected format error for 1-field row")
+	}
+}
+
+func FuzzParseTeams(f *testing.F) {
+	valid, _ := json.Marshal([][]interface{}{sampleRow()})
+	f.Add(valid)
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`[[1]]`))
+	f.Add([]byte(`[[null,null,null]]`))
+	f.Add([]byte(`[[1,"A","B","1-1","NaN",0,"Inf",0,"0.5"]]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var raw [][]interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return
+		}
+		teams, report, err := parseTeams(raw, true, zap.NewNop())
+		if err != nil {
+			return
+		}
+		if len(teams)+report.Skipped != len(raw) {
+			t.Fatalf("teams (%d) + skipped (%d) != rows (%d)", len(teams), report.Skipped, len(raw))
+		}
+		for _, team := range teams {
+			if team.Team == "" {
+				t.Fatal("accepted team with empty name")
+			}
+			for name, p := range map[string]*float64{
+				"adjoe": &team.AdjOE, "adjde": &team.AdjDE, "tempo": &team.AdjTempo,
+				"barthag": &team.Barthag, "efg": team.EFG, "3pr": team.ThreePR,
+			} {
+				if p != nil && (math.IsNaN(*p) || math.IsInf(*p, 0)) {
+					t.Fatalf("%s: non-finite %s = %v", team.Team, name, *p)
+				}
+			}
+			if team.AdjTempo < 55 || team.AdjTempo > 85 || team.Barthag <= 0 || team.Barthag > 1 {
+				t.Fatalf("%s: strict mode accepted tempo %v, barthag %v", team.Team, team.AdjTempo, team.Barthag)
+			}
+			if team.AdjOE < 70 || team.AdjOE > 140 || team.AdjDE < 70 || team.AdjDE > 140 {
+				t.Fatalf("%s: efficiency out of range (%v, %v)", team.Team, team.AdjOE, team.AdjDE)
+			}
+		}
+	})
+}
+
+func FuzzToFloat(f *testing.F) {
+	for _, s := range []string{"1.5", " 42 ", "NaN", "-Inf", "1e309", "", "abc", "0x1p-2"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, v := range []interface{}{s, toFloat(s)} {
+			got, ok := toFloatOK(v)
+			if math.IsNaN(got) || math.IsInf(got, 0) {
+				t.Fatalf("toFloatOK(%q) = %v, want finite", v, got)
+			}
+			if !ok && got != 0 {
+				t.Fatalf("toFloatOK(%q) = %v, false; want 0 when not ok", v, got)
+			}
+		}
+		// toInt must never overflow into garbage values
+		if i := toInt(s); i > maxIntField || i < -maxIntField {
+			t.Fatalf("toInt(%q) = %d out of range", s, i)
+		}
+	})
+}
+
+func FuzzParseRecord(f *testing.F) {
+	for _, s := range []string{"20-2", " 3 - 4 ", "-5-3", "10", "", "a-b", "99999999999999999999-1"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		wins, losses := parseRecord(s)
+		if wins < 0 || losses < 0 {
+			t.Fatalf("parseRecord(%q) = %d-%d, want non-negative", s, wins, losses)
+		}
+	})
+}
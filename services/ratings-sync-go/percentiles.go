@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// normalizedMetric is a team_ratings column that gets a national percentile and z-score
+type normalizedMetric struct {
+	Column string
+	// HigherIsBetter orients both values so 100th percentile / positive z = better.
+	// For neutral metrics (tempo, 3P rate) it is true: high percentile = high value.
+	HigherIsBetter bool
+}
+
+// normalizedMetrics is the whitelist of columns normalized per snapshot. Column
+// names are interpolated into SQL, so they must only ever come from this list.
+var normalizedMetrics = []normalizedMetric{
+	{"adj_o", true},
+	{"adj_d", false},
+	{"net_rating", true},
+	{"tempo", true},
+	{"barthag", true},
+	{"efg", true},
+	{"efgd", false},
+	{"tor", false},
+	{"tord", true},
+	{"orb", true},
+	{"drb", true},
+	{"ftr", true},
+	{"ftrd", false},
+	{"two_pt_pct", true},
+	{"two_pt_pct_d", false},
+	{"three_pt_pct", true},
+	{"three_pt_pct_d", false},
+	{"three_pt_rate", true},
+	{"three_pt_rate_d", true},
+}
+
+// ComputeRatingPercentiles stores a national percentile (0-100) and z-score for
+// every normalized metric of every team in the asOf snapshot, so reports and
+// features don't recompute distributions at query time. Teams with a NULL metric
+// (strict parsing) are left out of that metric's distribution.
+func (r *RatingsSync) ComputeRatingPercentiles(ctx context.Context, asOf time.Time) error {
+	asOfDate := asOf.UTC().Format("2006-01-02")
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows := int64(0)
+	for _, m := range normalizedMetrics {
+		order, sign := "ASC", "1"
+		if !m.HigherIsBetter {
+			order, sign = "DESC", "-1"
+		}
+		// percent_rank() is 0 for the worst team and 1 for the best; ties share a rank
+		tag, err := tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO team_rating_percentiles (team_id, rating_date, metric, value, percentile, z_score)
+			SELECT team_id, rating_date, $2, %[1]s,
+			       ROUND((percent_rank() OVER (ORDER BY %[1]s %[2]s) * 100)::numeric, 2),
+			       ROUND((%[3]s * (%[1]s - AVG(%[1]s) OVER ()) / NULLIF(STDDEV_POP(%[1]s) OVER (), 0))::numeric, 3)
+			FROM team_ratings
+			WHERE rating_date = $1 AND %[1]s IS NOT NULL
+			ON CONFLICT (team_id, rating_date, metric) DO UPDATE SET
+				value = EXCLUDED.value,
+				percentile = EXCLUDED.percentile,
+				z_score = EXCLUDED.z_score
+		`, m.Column, order, sign), asOfDate, m.Column)
+		if err != nil {
+			return fmt.Errorf("storing %s percentiles: %w", m.Column, err)
+		}
+		rows += tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	r.logger.Info("Stored rating percentiles",
+		zap.String("date", asOfDate),
+		zap.Int("metrics", len(normalizedMetrics)),
+		zap.Int64("rows", rows))
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Game sites used for quadrant bucketing (from the team's perspective)
+const (
+	siteHome    = "home"
+	siteAway    = "away"
+	siteNeutral = "neutral"
+)
+
+// QuadrantRecord holds a team's W/L split by NET-style quadrant.
+// Index 0 = Q1 ... index 3 = Q4.
+type QuadrantRecord struct {
+	Wins   [4]int
+	Losses [4]int
+}
+
+// quadrantFor returns the quadrant (1-4) for a game against an opponent of the
+// given rank at the given site. Thresholds follow the NCAA NET team sheet.
+func quadrantFor(oppRank int, site string) int {
+	// Upper rank bound for Q1, Q2, Q3 at each site; anything beyond is Q4
+	var bounds [3]int
+	switch site {
+	case siteHome:
+		bounds = [3]int{30, 75, 160}
+	case siteNeutral:
+		bounds = [3]int{50, 100, 200}
+	default:
+		bounds = [3]int{75, 135, 240}
+	}
+
+	for i, bound := range bounds {
+		if oppRank >= 1 && oppRank <= bound {
+			return i + 1
+		}
+	}
+	return 4
+}
+
+// seasonWindow returns the [start, end) commence_time window for an NCAA season.
+// Season 2026 covers Oct 1, 2025 through Apr 30, 2026 (mirrors getCurrentSeason's May cutover).
+func seasonWindow(season int) (time.Time, time.Time) {
+	start := time.Date(season-1, time.October, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(season, time.May, 1, 0, 0, 0, 0, time.UTC)
+	return start, end
+}
+
+// ComputeQuadrantRecords builds quadrant records for every rated team from
+// completed games in the current season, using torvik_rank on asOf as the
+// opponent ranking, and upserts them into team_quadrant_records.
+func (r *RatingsSync) ComputeQuadrantRecords(ctx context.Context, asOf time.Time) error {
+	asOfDate := asOf.UTC().Format("2006-01-02")
+	seasonStart, seasonEnd := seasonWindow(r.config.Season)
+	// Only count games played before the end of the as-of day
+	cutoff := asOf.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if cutoff.Before(seasonEnd) {
+		seasonEnd = cutoff
+	}
+
+	ranks := make(map[string]int)
+	rows, err := r.db.Query(ctx, `
+		SELECT team_id::text, torvik_rank
+		FROM team_ratings
+		WHERE rating_date = $1 AND torvik_rank IS NOT NULL
+	`, asOfDate)
+	if err != nil {
+		return fmt.Errorf("loading ranks: %w", err)
+	}
+	for rows.Next() {
+		var teamID string
+		var rank int
+		if err := rows.Scan(&teamID, &rank); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning rank: %w", err)
+		}
+		ranks[teamID] = rank
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading ranks: %w", err)
+	}
+
+	if len(ranks) == 0 {
+		r.logger.Warn("No ranked teams for quadrant computation", zap.String("date", asOfDate))
+		return nil
+	}
+
+	records := make(map[string]*QuadrantRecord, len(ranks))
+	for teamID := range ranks {
+		records[teamID] = &QuadrantRecord{}
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT home_team_id::text, away_team_id::text, COALESCE(is_neutral, FALSE),
+		       home_score, away_score
+		FROM games
+		WHERE status IN ('completed', 'final')
+		  AND home_score IS NOT NULL AND away_score IS NOT NULL
+		  AND commence_time >= $1 AND commence_time < $2
+	`, seasonStart, seasonEnd)
+	if err != nil {
+		return fmt.Errorf("loading games: %w", err)
+	}
+	games := 0
+	for rows.Next() {
+		var homeID, awayID string
+		var neutral bool
+		var homeScore, awayScore int
+		if err := rows.Scan(&homeID, &awayID, &neutral, &homeScore, &awayScore); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning game: %w", err)
+		}
+		if homeScore == awayScore {
+			continue
+		}
+
+		homeSite, awaySite := siteHome, siteAway
+		if neutral {
+			homeSite, awaySite = siteNeutral, siteNeutral
+		}
+		homeWon := homeScore > awayScore
+
+		// Non-D1 opponents have no rank and are excluded (NET team-sheet behavior)
+		recorded := false
+		if rec, ok := records[homeID]; ok {
+			if oppRank, ok := ranks[awayID]; ok {
+				rec.add(quadrantFor(oppRank, homeSite), homeWon)
+				recorded = true
+			}
+		}
+		if rec, ok := records[awayID]; ok {
+			if oppRank, ok := ranks[homeID]; ok {
+				rec.add(quadrantFor(oppRank, awaySite), !homeWon)
+				recorded = true
+			}
+		}
+		if recorded {
+			games++
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading games: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for teamID, rec := range records {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO team_quadrant_records (
+				team_id, season, as_of_date,
+				q1_wins, q1_losses, q2_wins, q2_losses,
+				q3_wins, q3_losses, q4_wins, q4_losses
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (team_id, season, as_of_date) DO UPDATE SET
+				q1_wins = EXCLUDED.q1_wins,
+				q1_losses = EXCLUDED.q1_losses,
+				q2_wins = EXCLUDED.q2_wins,
+				q2_losses = EXCLUDED.q2_losses,
+				q3_wins = EXCLUDED.q3_wins,
+				q3_losses = EXCLUDED.q3_losses,
+				q4_wins = EXCLUDED.q4_wins,
+				q4_losses = EXCLUDED.q4_losses
+		`, teamID, r.config.Season, asOfDate,
+			rec.Wins[0], rec.Losses[0], rec.Wins[1], rec.Losses[1],
+			rec.Wins[2], rec.Losses[2], rec.Wins[3], rec.Losses[3])
+		if err != nil {
+			return fmt.Errorf("storing quadrant record: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	r.logger.Info("Stored quadrant records",
+		zap.String("date", asOfDate),
+		zap.Int("teams", len(records)),
+		zap.Int("games", games))
+	return nil
+}
+
+func (q *QuadrantRecord) add(quadrant int, won bool) {
+	if won {
+		q.Wins[quadrant-1]++
+	} else {
+		q.Losses[quadrant-1]++
+	}
+}
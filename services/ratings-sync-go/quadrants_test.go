@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestQuadrantFor(t *testing.T) {
+	cases := []struct {
+		rank int
+		site string
+		want int
+	}{
+		// Home: Q1 1-30, Q2 31-75, Q3 76-160, Q4 161+
+		{1, siteHome, 1},
+		{30, siteHome, 1},
+		{31, siteHome, 2},
+		{75, siteHome, 2},
+		{76, siteHome, 3},
+		{160, siteHome, 3},
+		{161, siteHome, 4},
+		// Neutral: Q1 1-50, Q2 51-100, Q3 101-200, Q4 201+
+		{50, siteNeutral, 1},
+		{51, siteNeutral, 2},
+		{100, siteNeutral, 2},
+		{101, siteNeutral, 3},
+		{200, siteNeutral, 3},
+		{201, siteNeutral, 4},
+		// Away: Q1 1-75, Q2 76-135, Q3 136-240, Q4 241+
+		{75, siteAway, 1},
+		{76, siteAway, 2},
+		{135, siteAway, 2},
+		{136, siteAway, 3},
+		{240, siteAway, 3},
+		{241, siteAway, 4},
+		// Unranked (0) never lands in Q1
+		{0, siteHome, 4},
+	}
+	for _, c := range cases {
+		if got := quadrantFor(c.rank, c.site); got != c.want {
+			t.Errorf("quadrantFor(%d, %s) = Q%d, want Q%d", c.rank, c.site, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// schemaTable lists the columns (and information_schema data types) the sync
+// reads or writes on one table.
+type schemaTable struct {
+	name      string
+	migration string // migration that creates or last extends the table
+	columns   map[string]string
+	// optional tables only feed best-effort features; missing ones are warnings
+	// and the feature (derived step, outage log, ...) is skipped
+	optional bool
+	feature  string // what is skipped when an optional table is unavailable
+}
+
+// expectedSchema is what this service's SQL depends on. Keep it in sync with the
+// queries in main.go and the derived-table files.
+var expectedSchema = []schemaTable{
+	{
+		name:      "teams",
+		migration: "001",
+		columns: map[string]string{
+			"id":              "uuid",
+			"canonical_name":  "text",
+			"barttorvik_name": "text",
+			"conference":      "text",
+		},
+	},
+	{
+		name:      "team_aliases",
+		migration: "001",
+		columns: map[string]string{
+			"team_id": "uuid",
+			"alias":   "text",
+			"source":  "text",
+		},
+	},
+	{
+		name:      "team_ratings",
+		migration: "009",
+		columns: map[string]string{
+			"team_id":         "uuid",
+			"rating_date":     "date",
+			"adj_o":           "numeric",
+			"adj_d":           "numeric",
+			"tempo":           "numeric",
+			"net_rating":      "numeric",
+			"torvik_rank":     "integer",
+			"wins":            "integer",
+			"losses":          "integer",
+			"games_played":    "integer",
+			"efg":             "numeric",
+			"efgd":            "numeric",
+			"tor":             "numeric",
+			"tord":            "numeric",
+			"orb":             "numeric",
+			"drb":             "numeric",
+			"ftr":             "numeric",
+			"ftrd":            "numeric",
+			"two_pt_pct":      "numeric",
+			"two_pt_pct_d":    "numeric",
+			"three_pt_pct":    "numeric",
+			"three_pt_pct_d":  "numeric",
+			"three_pt_rate":   "numeric",
+			"three_pt_rate_d": "numeric",
+			"barthag":         "numeric",
+			"wab":             "numeric",
+			"raw_barttorvik":  "jsonb",
+		},
+	},
+	{
+		name:      "games",
+		migration: "038",
+		columns: map[string]string{
+			"home_team_id":  "uuid",
+			"away_team_id":  "uuid",
+			"is_neutral":    "boolean",
+			"status":        "text",
+			"home_score":    "integer",
+			"away_score":    "integer",
+			"commence_time": "timestamp with time zone",
+			"total_score":   "integer",
+		},
+	},
+	{
+		// Not used by this service, but run_today.py writes it right after the
+		// sync; fail here rather than after a full ratings pull
+		name:      "predictions",
+		migration: "001",
+		columns: map[string]string{
+			"game_id":       "uuid",
+			"model_version": "text",
+			"created_at":    "timestamp with time zone",
+		},
+	},
+	{name: "team_quadrant_records", migration: "029", optional: true, feature: "quadrant records", columns: map[string]string{"as_of_date": "date"}},
+	{name: "sync_checkpoints", migration: "030", optional: true, feature: "backfill checkpoints", columns: map[string]string{"job_name": "text"}},
+	{name: "conference_metrics", migration: "031", optional: true, feature: "conference metrics", columns: map[string]string{"as_of_date": "date"}},
+	{name: "ratings_change_events", migration: "032", optional: true, feature: "ratings change events", columns: map[string]string{"processed_at": "timestamp with time zone"}},
+	{name: "team_rating_percentiles", migration: "033", optional: true, feature: "rating percentiles", columns: map[string]string{"metric": "text"}},
+	{name: "team_recent_form", migration: "034", optional: true, feature: "recent form", columns: map[string]string{"adjusted_margin": "numeric"}},
+	{name: "provider_outages", migration: "039", optional: true, feature: "provider outage log", columns: map[string]string{"ended_at": "timestamp with time zone", "confirmed_at": "timestamp with time zone"}},
+	{name: "team_season_projections", migration: "036", optional: true, feature: "season projections", columns: map[string]string{"win_distribution": "jsonb"}},
+	{name: "team_alias_conflicts", migration: "037", optional: true, feature: "alias conflict check", columns: map[string]string{"canonical_owner": "text", "affects_ratings": "boolean"}},
+}
+
+// schemaProblems compares the live columns (table -> column -> data_type) with
+// expected and returns required and optional mismatches as readable messages,
+// plus the optional tables that had any problem.
+func schemaProblems(expected []schemaTable, live map[string]map[string]string) (required, optional []string, unavailable map[string]bool) {
+	unavailable = make(map[string]bool)
+	for _, t := range expected {
+		var problems []string
+		cols, ok := live[t.name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("table %s is missing (apply migration %s)", t.name, t.migration))
+		} else {
+			for col, want := range t.columns {
+				got, ok := cols[col]
+				switch {
+				case !ok:
+					problems = append(problems, fmt.Sprintf("column %s.%s is missing (apply migration %s)", t.name, col, t.migration))
+				case got != want:
+					problems = append(problems, fmt.Sprintf("column %s.%s is %s, expected %s", t.name, col, got, want))
+				}
+			}
+		}
+		if t.optional {
+			optional = append(optional, problems...)
+			if len(problems) > 0 {
+				unavailable[t.name] = true
+			}
+		} else {
+			required = append(required, problems...)
+		}
+	}
+	// Map iteration order is random; keep messages stable for logs
+	sort.Strings(required)
+	sort.Strings(optional)
+	return required, optional, unavailable
+}
+
+// CheckSchema verifies the tables and columns the sync depends on exist with the
+// expected types. Missing required schema fails fast with a precise message
+// instead of an obscure scan error mid-sync; missing optional tables only warn,
+// and the features using them are skipped for this run (see schemaReady).
+func (r *RatingsSync) CheckSchema(ctx context.Context) error {
+	names := make([]string, 0, len(expectedSchema))
+	for _, t := range expectedSchema {
+		names = append(names, t.name)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+		  AND table_name = ANY($1)
+	`, names)
+	if err != nil {
+		return fmt.Errorf("reading information_schema: %w", err)
+	}
+	live := make(map[string]map[string]string)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning information_schema: %w", err)
+		}
+		if live[table] == nil {
+			live[table] = make(map[string]string)
+		}
+		live[table][column] = dataType
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading information_schema: %w", err)
+	}
+
+	required, optional, unavailable := schemaProblems(expectedSchema, live)
+	for _, p := range optional {
+		r.logger.Warn("Schema check: optional table problem", zap.String("problem", p))
+	}
+	for _, t := range expectedSchema {
+		if unavailable[t.name] {
+			r.logger.Warn("Schema check: feature disabled for this run", zap.String("table", t.name), zap.String("feature", t.feature))
+		}
+	}
+	r.unavailable = unavailable
+	if len(required) > 0 {
+		return fmt.Errorf("database schema incompatible: %s", strings.Join(required, "; "))
+	}
+	r.logger.Info("Schema check passed", zap.Int("optional_problems", len(optional)))
+	return nil
+}
+
+// schemaReady reports whether every table is usable. Before CheckSchema runs
+// nothing is known to be missing, so everything is ready.
+func (r *RatingsSync) schemaReady(tables ...string) bool {
+	for _, t := range tables {
+		if r.unavailable[t] {
+			return false
+		}
+	}
+	return true
+}
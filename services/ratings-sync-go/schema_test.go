@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestSchemaProblems(t *testing.T) {
+	expected := []schemaTable{
+		{name: "team_ratings", migration: "009", columns: map[string]string{"raw_barttorvik": "jsonb", "adj_o": "numeric"}},
+		{name: "games", migration: "038", columns: map[string]string{"total_score": "integer"}},
+		{name: "predictions", migration: "001", columns: map[string]string{"game_id": "uuid"}},
+		{name: "team_recent_form", migration: "034", optional: true, columns: map[string]string{"adjusted_margin": "numeric"}},
+		{name: "provider_outages", migration: "035", optional: true, columns: map[string]string{"ended_at": "timestamp with time zone"}},
+	}
+	live := map[string]map[string]string{
+		"team_ratings":     {"raw_barttorvik": "text", "adj_o": "numeric"},
+		"games":            {"home_score": "integer"},
+		"team_recent_form": {"adjusted_margin": "numeric"},
+	}
+
+	required, optional, unavailable := schemaProblems(expected, live)
+
+	wantRequired := []string{
+		"column games.total_score is missing (apply migration 038)",
+		"column team_ratings.raw_barttorvik is text, expected jsonb",
+		"table predictions is missing (apply migration 001)",
+	}
+	if !reflect.DeepEqual(required, wantRequired) {
+		t.Errorf("required = %q\nwant %q", required, wantRequired)
+	}
+	wantOptional := []string{"table provider_outages is missing (apply migration 035)"}
+	if !reflect.DeepEqual(optional, wantOptional) {
+		t.Errorf("optional = %q, want %q", optional, wantOptional)
+	}
+	if !reflect.DeepEqual(unavailable, map[string]bool{"provider_outages": true}) {
+		t.Errorf("unavailable = %v, want only provider_outages", unavailable)
+	}
+}
+
+func TestSchemaProblemsMatchingSchema(t *testing.T) {
+	live := make(map[string]map[string]string)
+	for _, tbl := range expectedSchema {
+		live[tbl.name] = tbl.columns
+	}
+	required, optional, unavailable := schemaProblems(expectedSchema, live)
+	if len(required)+len(optional)+len(unavailable) != 0 {
+		t.Fatalf("problems on a matching schema: %v %v %v", required, optional, unavailable)
+	}
+}
+
+func TestFinishSyncSkipsStepsWithUnavailableTables(t *testing.T) {
+	r := &RatingsSync{logger: zap.NewNop(), unavailable: map[string]bool{"team_recent_form": true}}
+
+	var ran []string
+	steps := []derivedStep{
+		{name: "quadrant records", tables: []string{"team_quadrant_records"}},
+		{name: "recent form", tables: []string{"team_recent_form"}},
+	}
+	for i := range steps {
+		name := steps[i].name
+		steps[i].run = func(context.Context, time.Time) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+
+	if err := r.finishSync(context.Background(), SyncStats{Fetched: 10, Stored: 10}, steps, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"quadrant records"}) {
+		t.Fatalf("ran %v, want only quadrant records", ran)
+	}
+}
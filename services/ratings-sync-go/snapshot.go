@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// previousSnapshot returns the date and row count of the most recent ratings day
+// before date, or ok=false if there is none (first run, fresh database).
+func previousSnapshot(ctx context.Context, tx pgx.Tx, date string) (prevDate string, count int, ok bool, err error) {
+	var d time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT rating_date, COUNT(*)
+		FROM team_ratings
+		WHERE rating_date < $1
+		GROUP BY rating_date
+		ORDER BY rating_date DESC
+		LIMIT 1
+	`, date).Scan(&d, &count)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", 0, false, nil
+	}
+	if err != nil {
+		return "", 0, false, err
+	}
+	return d.Format("2006-01-02"), count, true, nil
+}
+
+// checkSnapshotSize rejects a ratings day that is much smaller than the previous
+// one. Barttorvik occasionally serves its JSON mid-regeneration with only part of
+// D1 present; committing that would leave teams without a rating for the day.
+func checkSnapshotSize(stored, previous int, prevDate string, minPct float64) error {
+	if minPct <= 0 || previous == 0 {
+		return nil
+	}
+	pct := float64(stored) / float64(previous) * 100
+	if pct < minPct {
+		return &SyncError{
+			Code: ExitPartialSnapshot,
+			Err: fmt.Errorf("snapshot has %d teams, %.1f%% of %d on %s (below MIN_SNAPSHOT_PCT=%.1f); likely a partial Barttorvik update",
+				stored, pct, previous, prevDate, minPct),
+		}
+	}
+	return nil
+}
+
+// verifySnapshotSize compares the rows written in tx against the previous ratings day
+func (r *RatingsSync) verifySnapshotSize(ctx context.Context, tx pgx.Tx, date string, stored int) error {
+	if r.config.MinSnapshotPct <= 0 {
+		return nil
+	}
+	prevDate, previous, ok, err := previousSnapshot(ctx, tx, date)
+	if err != nil {
+		return fmt.Errorf("loading previous snapshot size: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+	r.logger.Info("Snapshot size check",
+		zap.Int("stored", stored),
+		zap.Int("previous", previous),
+		zap.String("previous_date", prevDate))
+	return checkSnapshotSize(stored, previous, prevDate, r.config.MinSnapshotPct)
+}
+
+// waitForRetry sleeps for the partial-snapshot retry delay, or returns early if ctx ends
+func (r *RatingsSync) waitForRetry(ctx context.Context) error {
+	select {
+	case <-time.After(r.config.PartialRetryDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
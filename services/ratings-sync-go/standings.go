@@ -0,0 +1,488 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// standingsRuns is how many times the remaining schedule is simulated
+	standingsRuns = 10000
+	// standingsRefreshDays is how old (in days) the latest projection may get before
+	// a sync recomputes it
+	standingsRefreshDays = 7
+	// marginStdDev is the spread of actual margins around the ratings expectation (points)
+	marginStdDev = 11.0
+)
+
+// simTeam is one team's fixed inputs to the season simulation
+type simTeam struct {
+	ID         string
+	Conference string
+	Ratings    gameRatings
+	Wins       int // Current season record (Barttorvik)
+	Losses     int
+	ConfWins   int // Current conference record (completed games between conference members)
+	ConfLosses int
+}
+
+// simGame is one unplayed game; Home/Away index into the simTeam slice
+type simGame struct {
+	Home, Away  int
+	HomeWinProb float64
+	Conference  bool
+}
+
+// Projection is a team's simulated end-of-season outlook
+type Projection struct {
+	RemainingGames  int
+	ProjWins        float64
+	ProjLosses      float64
+	ProjWinsP10     int
+	ProjWinsP90     int
+	ProjConfWins    float64
+	ProjConfLosses  float64
+	AvgConfRank     float64
+	ConfTitleProb   float64         // Share of runs finishing first in conference wins (ties split)
+	WinDistribution map[int]float64 // Final wins -> probability
+}
+
+// winProbability converts an expected margin into a win probability under a
+// normal margin distribution.
+func winProbability(expected float64) float64 {
+	return 0.5 * (1 + math.Erf(expected/(marginStdDev*math.Sqrt2)))
+}
+
+// simulateSeason plays the remaining games runs times and summarizes each team's
+// final record distribution and conference finish.
+func simulateSeason(teams []simTeam, games []simGame, runs int, rng *rand.Rand) []Projection {
+	n := len(teams)
+	projections := make([]Projection, n)
+	finalWins := make([][]int, n)
+	for i := range projections {
+		projections[i].WinDistribution = make(map[int]float64)
+		finalWins[i] = make([]int, 0, runs)
+	}
+	for _, g := range games {
+		projections[g.Home].RemainingGames++
+		projections[g.Away].RemainingGames++
+	}
+
+	byConf := make(map[string][]int)
+	for i, t := range teams {
+		if t.Conference != "" {
+			byConf[t.Conference] = append(byConf[t.Conference], i)
+		}
+	}
+
+	wins := make([]int, n)
+	confWins := make([]int, n)
+	confLosses := make([]int, n)
+	for run := 0; run < runs; run++ {
+		for i, t := range teams {
+			wins[i] = t.Wins
+			confWins[i] = t.ConfWins
+			confLosses[i] = t.ConfLosses
+		}
+		for _, g := range games {
+			winner, loser := g.Home, g.Away
+			if rng.Float64() >= g.HomeWinProb {
+				winner, loser = loser, winner
+			}
+			wins[winner]++
+			if g.Conference {
+				confWins[winner]++
+				confLosses[loser]++
+			}
+		}
+
+		for i := range teams {
+			finalWins[i] = append(finalWins[i], wins[i])
+			projections[i].ProjConfWins += float64(confWins[i])
+			projections[i].ProjConfLosses += float64(confLosses[i])
+		}
+
+		// Conference finish: rank by conference wins, ties share the better rank
+		// and split the title
+		for _, members := range byConf {
+			best := -1
+			leaders := 0
+			for _, i := range members {
+				rank := 1
+				for _, j := range members {
+					if confWins[j] > confWins[i] {
+						rank++
+					}
+				}
+				projections[i].AvgConfRank += float64(rank)
+				if confWins[i] > best {
+					best, leaders = confWins[i], 1
+				} else if confWins[i] == best {
+					leaders++
+				}
+			}
+			for _, i := range members {
+				if confWins[i] == best {
+					projections[i].ConfTitleProb += 1 / float64(leaders)
+				}
+			}
+		}
+	}
+
+	for i, t := range teams {
+		p := &projections[i]
+		total := float64(t.Wins + t.Losses + p.RemainingGames)
+		sum := 0
+		for _, w := range finalWins[i] {
+			sum += w
+			p.WinDistribution[w] += 1 / float64(runs)
+		}
+		p.ProjWins = float64(sum) / float64(runs)
+		p.ProjLosses = total - p.ProjWins
+		sort.Ints(finalWins[i])
+		p.ProjWinsP10 = finalWins[i][runs/10]
+		p.ProjWinsP90 = finalWins[i][runs*9/10]
+		p.ProjConfWins /= float64(runs)
+		p.ProjConfLosses /= float64(runs)
+		p.ConfTitleProb /= float64(runs)
+		if t.Conference != "" {
+			p.AvgConfRank /= float64(runs)
+		}
+	}
+	return projections
+}
+
+// ProjectStandingsIfStale recomputes season projections when the latest stored
+// projection for the season is standingsRefreshDays or more old (weekly cadence).
+// Ages are whole UTC dates, matching as_of_date, so the session time zone
+// doesn't matter.
+func (r *RatingsSync) ProjectStandingsIfStale(ctx context.Context, asOf time.Time) error {
+	var ageDays *int
+	if err := r.db.QueryRow(ctx, `
+		SELECT $2::date - MAX(as_of_date) FROM team_season_projections WHERE season = $1
+	`, r.config.Season, asOf.UTC().Format("2006-01-02")).Scan(&ageDays); err != nil {
+		return fmt.Errorf("checking latest projection: %w", err)
+	}
+	if ageDays != nil && *ageDays < standingsRefreshDays {
+		r.logger.Debug("Season projections are fresh", zap.Int("age_days", *ageDays))
+		return nil
+	}
+	return r.ProjectStandings(ctx, asOf)
+}
+
+// ProjectStandings simulates the rest of the season from the season's latest
+// ratings and the remaining schedule in games, and upserts per-team projections
+// into team_season_projections.
+//
+// The remaining schedule is whatever games are already ingested (odds ingestion
+// adds games as books list them), so early-season projections cover only the
+// near-term slate.
+func (r *RatingsSync) ProjectStandings(ctx context.Context, asOf time.Time) error {
+	asOfDate := asOf.UTC().Format("2006-01-02")
+	seasonStart, seasonEnd := seasonWindow(r.config.Season)
+
+	var teams []simTeam
+	index := make(map[string]int)
+	rows, err := r.db.Query(ctx, `
+		SELECT tr.team_id::text,
+		       COALESCE(NULLIF(tr.raw_barttorvik->>'conf', ''), t.conference, ''),
+		       tr.adj_o, tr.adj_d, tr.tempo,
+		       COALESCE(tr.wins, 0), COALESCE(tr.losses, 0)
+		FROM team_ratings tr
+		JOIN teams t ON t.id = tr.team_id
+		WHERE tr.rating_date = (
+			SELECT MAX(rating_date) FROM team_ratings
+			WHERE rating_date <= $1 AND rating_date >= $2 AND rating_date < $3
+		)
+		  AND tr.adj_o IS NOT NULL AND tr.adj_d IS NOT NULL AND tr.tempo IS NOT NULL
+	`, asOfDate, seasonStart.Format("2006-01-02"), seasonEnd.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("loading ratings: %w", err)
+	}
+	for rows.Next() {
+		var t simTeam
+		if err := rows.Scan(&t.ID, &t.Conference, &t.Ratings.AdjO, &t.Ratings.AdjD, &t.Ratings.Tempo, &t.Wins, &t.Losses); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning rating: %w", err)
+		}
+		index[t.ID] = len(teams)
+		teams = append(teams, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading ratings: %w", err)
+	}
+	if len(teams) == 0 {
+		r.logger.Warn("No ratings for season projections", zap.String("date", asOfDate), zap.Int("season", r.config.Season))
+		return nil
+	}
+
+	var games []simGame
+	rows, err = r.db.Query(ctx, `
+		SELECT home_team_id::text, away_team_id::text, commence_time, COALESCE(is_neutral, FALSE),
+		       COALESCE(status IN ('completed', 'final') AND home_score IS NOT NULL AND away_score IS NOT NULL, FALSE),
+		       COALESCE(home_score, 0), COALESCE(away_score, 0)
+		FROM games
+		WHERE commence_time >= $1 AND commence_time < $2
+		  AND COALESCE(status, 'scheduled') NOT IN ('cancelled', 'postponed')
+	`, seasonStart, seasonEnd)
+	if err != nil {
+		return fmt.Errorf("loading games: %w", err)
+	}
+	for rows.Next() {
+		var homeID, awayID string
+		var at time.Time
+		var neutral, completed bool
+		var homeScore, awayScore int
+		if err := rows.Scan(&homeID, &awayID, &at, &neutral, &completed, &homeScore, &awayScore); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning game: %w", err)
+		}
+		h, okHome := index[homeID]
+		a, okAway := index[awayID]
+		if !okHome || !okAway {
+			continue // Non-D1 opponent: not part of any standings
+		}
+		conf := teams[h].Conference != "" && teams[h].Conference == teams[a].Conference
+
+		if completed {
+			if conf && homeScore != awayScore {
+				winner, loser := h, a
+				if awayScore > homeScore {
+					winner, loser = a, h
+				}
+				teams[winner].ConfWins++
+				teams[loser].ConfLosses++
+			}
+			continue
+		}
+		if at.Before(asOf) {
+			continue // Already played but unscored: Barttorvik's record counts it
+		}
+
+		site := 1
+		if neutral {
+			site = 0
+		}
+		games = append(games, simGame{
+			Home:        h,
+			Away:        a,
+			HomeWinProb: winProbability(expectedMargin(teams[h].Ratings, teams[a].Ratings, site)),
+			Conference:  conf,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading games: %w", err)
+	}
+
+	// Seeded by date so re-running a day reproduces the same projections
+	rng := rand.New(rand.NewSource(asOf.UTC().Truncate(24 * time.Hour).Unix()))
+	projections := simulateSeason(teams, games, standingsRuns, rng)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for i, t := range teams {
+		p := projections[i]
+		dist, err := json.Marshal(p.WinDistribution)
+		if err != nil {
+			return fmt.Errorf("encoding win distribution: %w", err)
+		}
+		var avgRank *float64
+		if t.Conference != "" {
+			avgRank = &p.AvgConfRank
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO team_season_projections (
+				team_id, season, as_of_date, conference, runs,
+				current_wins, current_losses, current_conf_wins, current_conf_losses,
+				remaining_games, proj_wins, proj_losses, proj_wins_p10, proj_wins_p90,
+				proj_conf_wins, proj_conf_losses, avg_conf_rank, conf_title_prob,
+				win_distribution
+			) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10,
+				$11, $12, $13, $14, $15, $16, $17, $18, $19)
+			ON CONFLICT (team_id, season, as_of_date) DO UPDATE SET
+				conference = EXCLUDED.conference,
+				runs = EXCLUDED.runs,
+				current_wins = EXCLUDED.current_wins,
+				current_losses = EXCLUDED.current_losses,
+				current_conf_wins = EXCLUDED.current_conf_wins,
+				current_conf_losses = EXCLUDED.current_conf_losses,
+				remaining_games = EXCLUDED.remaining_games,
+				proj_wins = EXCLUDED.proj_wins,
+				proj_losses = EXCLUDED.proj_losses,
+				proj_wins_p10 = EXCLUDED.proj_wins_p10,
+				proj_wins_p90 = EXCLUDED.proj_wins_p90,
+				proj_conf_wins = EXCLUDED.proj_conf_wins,
+				proj_conf_losses = EXCLUDED.proj_conf_losses,
+				avg_conf_rank = EXCLUDED.avg_conf_rank,
+				conf_title_prob = EXCLUDED.conf_title_prob,
+				win_distribution = EXCLUDED.win_distribution
+		`, t.ID, r.config.Season, asOfDate, t.Conference, standingsRuns,
+			t.Wins, t.Losses, t.ConfWins, t.ConfLosses,
+			p.RemainingGames, p.ProjWins, p.ProjLosses, p.ProjWinsP10, p.ProjWinsP90,
+			p.ProjConfWins, p.ProjConfLosses, avgRank, p.ConfTitleProb,
+			string(dist))
+		if err != nil {
+			return fmt.Errorf("storing projection: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	r.logger.Info("Stored season projections",
+		zap.String("date", asOfDate),
+		zap.Int("teams", len(teams)),
+		zap.Int("remaining_games", len(games)),
+		zap.Int("runs", standingsRuns))
+	return nil
+}
+
+// StandingsRow is one team's line in the projected_conference_standings view
+type StandingsRow struct {
+	AsOf              time.Time
+	Conference        string
+	Team              string
+	CurrentWins       int
+	CurrentLosses     int
+	CurrentConfWins   int
+	CurrentConfLosses int
+	ProjWins          float64
+	ProjLosses        float64
+	ProjWinsP10       int
+	ProjWinsP90       int
+	ProjConfWins      float64
+	ProjConfLosses    float64
+	AvgConfRank       *float64 // nil for independents
+	ConfTitleProb     float64
+}
+
+// LoadProjectedStandings reads the latest projection for season, optionally
+// limited to one conference (case-insensitive)
+func (r *RatingsSync) LoadProjectedStandings(ctx context.Context, season int, conference string) ([]StandingsRow, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT as_of_date, COALESCE(conference, ''), team,
+		       current_wins, current_losses, current_conf_wins, current_conf_losses,
+		       proj_wins, proj_losses, proj_wins_p10, proj_wins_p90,
+		       proj_conf_wins, proj_conf_losses, avg_conf_rank, conf_title_prob
+		FROM projected_conference_standings
+		WHERE season = $1 AND ($2 = '' OR UPPER(conference) = UPPER($2))
+		ORDER BY conference, proj_conf_wins DESC, proj_wins DESC
+	`, season, conference)
+	if err != nil {
+		return nil, fmt.Errorf("loading projected standings: %w", err)
+	}
+	defer rows.Close()
+
+	var standings []StandingsRow
+	for rows.Next() {
+		var s StandingsRow
+		if err := rows.Scan(&s.AsOf, &s.Conference, &s.Team,
+			&s.CurrentWins, &s.CurrentLosses, &s.CurrentConfWins, &s.CurrentConfLosses,
+			&s.ProjWins, &s.ProjLosses, &s.ProjWinsP10, &s.ProjWinsP90,
+			&s.ProjConfWins, &s.ProjConfLosses, &s.AvgConfRank, &s.ConfTitleProb); err != nil {
+			return nil, fmt.Errorf("scanning projected standings: %w", err)
+		}
+		standings = append(standings, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading projected standings: %w", err)
+	}
+	return standings, nil
+}
+
+// printStandings writes one table per conference, in the order given
+func printStandings(w io.Writer, season int, standings []StandingsRow) {
+	fmt.Fprintf(w, "Projected standings, season %d (as of %s)\n", season, standings[0].AsOf.Format("2006-01-02"))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	conference := ""
+	for i, s := range standings {
+		if i == 0 || s.Conference != conference {
+			conference = s.Conference
+			name := conference
+			if name == "" {
+				name = "Independents"
+			}
+			fmt.Fprintf(tw, "\n%s\n", name)
+			fmt.Fprintln(tw, "  Team\tRecord\tConf\tProj W-L\tWins p10-p90\tProj conf\tAvg finish\tTitle")
+		}
+		rank := "-"
+		if s.AvgConfRank != nil {
+			rank = fmt.Sprintf("%.1f", *s.AvgConfRank)
+		}
+		fmt.Fprintf(tw, "  %s\t%d-%d\t%d-%d\t%.1f-%.1f\t%d-%d\t%.1f-%.1f\t%s\t%.1f%%\n",
+			s.Team, s.CurrentWins, s.CurrentLosses, s.CurrentConfWins, s.CurrentConfLosses,
+			s.ProjWins, s.ProjLosses, s.ProjWinsP10, s.ProjWinsP90,
+			s.ProjConfWins, s.ProjConfLosses, rank, s.ConfTitleProb*100)
+	}
+	tw.Flush()
+}
+
+// runStandingsCommand implements `ratings-sync standings [--season N] [--conference C]`,
+// printing the latest projected_conference_standings (migration 036).
+func runStandingsCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("standings", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	season := fs.Int("season", 0, "season year (default: SEASON, else the current season)")
+	conference := fs.String("conference", "", "only this conference, e.g. B12 (case-insensitive)")
+	if err := fs.Parse(args); err != nil {
+		return ExitFatal
+	}
+
+	profile, err := resolveProfile("")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFatal
+	}
+	config, err := loadConfig(profile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFatal
+	}
+	if *season == 0 {
+		*season = config.Season
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, config.DatabaseURL)
+	if err != nil {
+		fmt.Fprintln(stderr, "connecting to database:", err)
+		return ExitFatal
+	}
+	defer db.Close()
+	r := NewRatingsSync(db, zap.NewNop(), config)
+
+	standings, err := r.LoadProjectedStandings(ctx, *season, strings.TrimSpace(*conference))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFatal
+	}
+	if len(standings) == 0 {
+		fmt.Fprintf(stderr, "no projections for season %d", *season)
+		if *conference != "" {
+			fmt.Fprintf(stderr, ", conference %q", *conference)
+		}
+		fmt.Fprintln(stderr)
+		return ExitNoRows
+	}
+	printStandings(stdout, *season, standings)
+	return ExitOK
+}
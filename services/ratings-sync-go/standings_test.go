@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulateSeason(t *testing.T) {
+	teams := []simTeam{
+		{ID: "strong", Conference: "X", Wins: 10, Losses: 2, ConfWins: 4, ConfLosses: 1},
+		{ID: "weak", Conference: "X", Wins: 3, Losses: 9, ConfWins: 1, ConfLosses: 4},
+		{ID: "leader", Conference: "Y", Wins: 8, Losses: 4, ConfWins: 6, ConfLosses: 0},
+		{ID: "trailer", Conference: "Y", Wins: 6, Losses: 6, ConfWins: 0, ConfLosses: 6},
+	}
+	games := []simGame{
+		// Lopsided conference game: a 40-point expected margin
+		{Home: 0, Away: 1, HomeWinProb: winProbability(40), Conference: true},
+		{Home: 1, Away: 0, HomeWinProb: winProbability(-40), Conference: true},
+		// Coin-flip non-conference games; conference Y has nothing left in conference
+		{Home: 2, Away: 0, HomeWinProb: 0.5},
+		{Home: 3, Away: 1, HomeWinProb: 0.5},
+	}
+	const runs = 2000
+
+	got := simulateSeason(teams, games, runs, rand.New(rand.NewSource(7)))
+	again := simulateSeason(teams, games, runs, rand.New(rand.NewSource(7)))
+	if !reflect.DeepEqual(got, again) {
+		t.Fatal("same seed produced different projections")
+	}
+
+	// Every game produces exactly one win, so projected wins sum to the games played
+	played, projected := 0, 0.0
+	for i, team := range teams {
+		p := got[i]
+		played += team.Wins
+		projected += p.ProjWins
+		if total := float64(team.Wins + team.Losses + p.RemainingGames); math.Abs(p.ProjWins+p.ProjLosses-total) > 1e-9 {
+			t.Errorf("%s: %.2f-%.2f does not add up to %v games", team.ID, p.ProjWins, p.ProjLosses, total)
+		}
+		mass := 0.0
+		for _, prob := range p.WinDistribution {
+			mass += prob
+		}
+		if math.Abs(mass-1) > 1e-9 {
+			t.Errorf("%s: win distribution sums to %v", team.ID, mass)
+		}
+	}
+	if want := float64(played + len(games)); math.Abs(projected-want) > 1e-6 {
+		t.Errorf("projected wins sum to %.3f, want %v", projected, want)
+	}
+
+	strong, weak := got[0], got[1]
+	if strong.RemainingGames != 3 || weak.RemainingGames != 3 {
+		t.Errorf("remaining games = %d/%d, want 3/3", strong.RemainingGames, weak.RemainingGames)
+	}
+	if strong.ProjConfWins < 5.99 || weak.ProjConfWins > 1.01 {
+		t.Errorf("lopsided games: conf wins strong %.3f weak %.3f, want ~6 and ~1", strong.ProjConfWins, weak.ProjConfWins)
+	}
+	if strong.ConfTitleProb < 0.99 || strong.ProjWinsP10 < 11 {
+		t.Errorf("strong: title %.3f, p10 %d", strong.ConfTitleProb, strong.ProjWinsP10)
+	}
+
+	// Conference Y is decided: records and finish are fixed
+	leader, trailer := got[2], got[3]
+	if leader.ProjConfWins != 6 || leader.ProjConfLosses != 0 || trailer.ProjConfWins != 0 || trailer.ProjConfLosses != 6 {
+		t.Errorf("finished conference changed: leader %.1f-%.1f, trailer %.1f-%.1f",
+			leader.ProjConfWins, leader.ProjConfLosses, trailer.ProjConfWins, trailer.ProjConfLosses)
+	}
+	if leader.ConfTitleProb != 1 || leader.AvgConfRank != 1 || trailer.ConfTitleProb != 0 || trailer.AvgConfRank != 2 {
+		t.Errorf("finished conference: leader title %.3f rank %.2f, trailer title %.3f rank %.2f",
+			leader.ConfTitleProb, leader.AvgConfRank, trailer.ConfTitleProb, trailer.AvgConfRank)
+	}
+	// The coin-flip game still moves overall wins
+	if leader.ProjWins <= 8.3 || leader.ProjWins >= 8.7 {
+		t.Errorf("leader projected %.3f wins, want about 8.5", leader.ProjWins)
+	}
+}
+
+func TestPrintStandings(t *testing.T) {
+	rank := 1.2
+	standings := []StandingsRow{
+		{AsOf: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Conference: "B12", Team: "Houston",
+			CurrentWins: 20, CurrentLosses: 3, CurrentConfWins: 10, CurrentConfLosses: 1,
+			ProjWins: 27.1, ProjLosses: 3.9, ProjWinsP10: 25, ProjWinsP90: 29,
+			ProjConfWins: 17.2, ProjConfLosses: 2.8, AvgConfRank: &rank, ConfTitleProb: 0.621},
+		{Conference: "", Team: "Chicago State", CurrentWins: 4, CurrentLosses: 19},
+	}
+	var out bytes.Buffer
+	printStandings(&out, 2026, standings)
+
+	for _, want := range []string{"season 2026 (as of 2026-02-10)", "B12", "Houston", "27.1-3.9", "25-29", "62.1%", "Independents", "Chicago State"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}